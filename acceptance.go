@@ -0,0 +1,169 @@
+package hego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// AcceptanceCriterion decides whether a candidate solution replaces the current
+// solution of a trajectory based search. Criteria may keep state and are queried
+// once per iteration
+type AcceptanceCriterion interface {
+	// Verify checks the parameters of the criterion and returns nil if everything is ok
+	Verify() error
+	// Init resets the criterion for a search starting with the initial objective value
+	Init(initial float64)
+	// Accept returns true when the candidate objective value is accepted. current
+	// is the objective value of the current solution, best the best value found so far
+	Accept(candidate, current, best float64) bool
+}
+
+// HillClimbing accepts candidates that are at least as good as the current solution
+type HillClimbing struct{}
+
+// Verify always returns nil, hill climbing has no parameters
+func (h *HillClimbing) Verify() error {
+	return nil
+}
+
+// Init does nothing, hill climbing has no state
+func (h *HillClimbing) Init(initial float64) {}
+
+// Accept returns true when the candidate is not worse than the current solution
+func (h *HillClimbing) Accept(candidate, current, best float64) bool {
+	return candidate <= current
+}
+
+// AnnealingAcceptance accepts better candidates and worse candidates with
+// probability exp((current - candidate) / temperature) like SA. The temperature
+// is multiplied by AnnealingFactor after every decision
+type AnnealingAcceptance struct {
+	// Temperature is the initial temperature
+	Temperature float64
+	// AnnealingFactor is the cooling factor in (0, 1]
+	AnnealingFactor float64
+	temperature     float64
+}
+
+// Verify checks the validity of temperature and annealing factor
+func (a *AnnealingAcceptance) Verify() error {
+	if a.Temperature <= 0.0 {
+		return fmt.Errorf("temperature must be greater that 0.0, got %v", a.Temperature)
+	}
+	if a.AnnealingFactor > 1.0 || a.AnnealingFactor <= 0.0 {
+		return fmt.Errorf("annealing factor must be between 0.0 and 1.0, got %v", a.AnnealingFactor)
+	}
+	return nil
+}
+
+// Init resets the temperature
+func (a *AnnealingAcceptance) Init(initial float64) {
+	a.temperature = a.Temperature
+}
+
+// Accept applies the metropolis criterion and cools down
+func (a *AnnealingAcceptance) Accept(candidate, current, best float64) bool {
+	accept := candidate <= current || math.Exp((current-candidate)/a.temperature) > rand.Float64()
+	a.temperature *= a.AnnealingFactor
+	return accept
+}
+
+// RecordToRecord accepts candidates that are at most Deviation worse than the
+// best solution found so far (record-to-record travel by Dueck)
+type RecordToRecord struct {
+	// Deviation is the absolute allowed deviation from the record
+	Deviation float64
+}
+
+// Verify checks that the deviation is not negative
+func (r *RecordToRecord) Verify() error {
+	if r.Deviation < 0.0 {
+		return fmt.Errorf("deviation must not be negative, got %v", r.Deviation)
+	}
+	return nil
+}
+
+// Init does nothing, the record is passed to Accept
+func (r *RecordToRecord) Init(initial float64) {}
+
+// Accept returns true when the candidate is within Deviation of the record
+func (r *RecordToRecord) Accept(candidate, current, best float64) bool {
+	return candidate <= best+r.Deviation
+}
+
+// GreatDeluge accepts candidates that are better than the current solution or
+// below a water level (great deluge by Dueck). The level is lowered by Rain
+// after every decision
+type GreatDeluge struct {
+	// Level is the initial water level. When 0, the initial objective value is used
+	Level float64
+	// Rain is the amount the level is lowered by per decision
+	Rain  float64
+	level float64
+}
+
+// Verify checks that the rain is positive
+func (g *GreatDeluge) Verify() error {
+	if g.Rain <= 0.0 {
+		return fmt.Errorf("rain must be greater than 0.0, got %v", g.Rain)
+	}
+	return nil
+}
+
+// Init resets the water level
+func (g *GreatDeluge) Init(initial float64) {
+	g.level = g.Level
+	if g.level == 0.0 {
+		g.level = initial
+	}
+}
+
+// Accept returns true when the candidate is better than the current solution
+// or below the water level and lowers the level
+func (g *GreatDeluge) Accept(candidate, current, best float64) bool {
+	accept := candidate <= current || candidate <= g.level
+	g.level -= g.Rain
+	return accept
+}
+
+// LateAcceptance accepts candidates that are better than the current solution
+// or the current solution Length decisions ago (late acceptance hill climbing
+// by Burke and Bykov)
+type LateAcceptance struct {
+	// Length is the number of past objective values to compare against
+	Length  int
+	history []float64
+	step    int
+}
+
+// Verify checks that the history length is positive
+func (l *LateAcceptance) Verify() error {
+	if l.Length < 1 {
+		return fmt.Errorf("length must be greater than 0, got %v", l.Length)
+	}
+	return nil
+}
+
+// Init fills the history with the initial objective value
+func (l *LateAcceptance) Init(initial float64) {
+	l.history = make([]float64, l.Length)
+	for i := range l.history {
+		l.history[i] = initial
+	}
+	l.step = 0
+}
+
+// Accept compares the candidate to the current and the late solution and
+// stores the resulting current objective value in the history
+func (l *LateAcceptance) Accept(candidate, current, best float64) bool {
+	v := l.step % l.Length
+	accept := candidate <= current || candidate <= l.history[v]
+	if accept {
+		l.history[v] = candidate
+	} else {
+		l.history[v] = current
+	}
+	l.step++
+	return accept
+}
@@ -0,0 +1,100 @@
+package hego
+
+import (
+	"testing"
+)
+
+func TestHillClimbing(t *testing.T) {
+	h := &HillClimbing{}
+	h.Init(10.0)
+	if !h.Accept(5.0, 10.0, 5.0) || !h.Accept(10.0, 10.0, 5.0) {
+		t.Error("expected hill climbing to accept equal and better candidates")
+	}
+	if h.Accept(11.0, 10.0, 5.0) {
+		t.Error("expected hill climbing to reject worse candidates")
+	}
+}
+
+func TestAnnealingAcceptance(t *testing.T) {
+	a := &AnnealingAcceptance{}
+	if a.Verify() == nil {
+		t.Error("expected verification to fail without temperature")
+	}
+	a.Temperature = 1e6
+	a.AnnealingFactor = 1e-6
+	if err := a.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+	a.Init(10.0)
+	if !a.Accept(11.0, 10.0, 10.0) {
+		t.Error("expected worse candidate to be accepted at a very high temperature")
+	}
+	// the temperature dropped to 1
+	for i := 0; i < 10; i++ {
+		if a.Accept(100.0, 10.0, 10.0) {
+			t.Error("expected much worse candidate to be rejected at low temperature")
+		}
+	}
+	a.Init(10.0)
+	if a.temperature != 1e6 {
+		t.Errorf("expected Init to reset the temperature, got %v", a.temperature)
+	}
+}
+
+func TestRecordToRecord(t *testing.T) {
+	r := &RecordToRecord{Deviation: -1.0}
+	if r.Verify() == nil {
+		t.Error("expected verification to fail with negative deviation")
+	}
+	r.Deviation = 2.0
+	if !r.Accept(11.0, 10.0, 9.0) {
+		t.Error("expected candidate within deviation of the record to be accepted")
+	}
+	if r.Accept(11.5, 12.0, 9.0) {
+		t.Error("expected candidate outside of deviation of the record to be rejected")
+	}
+}
+
+func TestGreatDeluge(t *testing.T) {
+	g := &GreatDeluge{}
+	if g.Verify() == nil {
+		t.Error("expected verification to fail without rain")
+	}
+	g.Rain = 1.0
+	g.Init(10.0)
+	if !g.Accept(10.0, 5.0, 5.0) {
+		t.Error("expected candidate at the water level to be accepted")
+	}
+	// the level dropped to 9
+	if g.Accept(9.5, 5.0, 5.0) {
+		t.Error("expected candidate above the water level to be rejected")
+	}
+	if !g.Accept(4.0, 5.0, 4.0) {
+		t.Error("expected improving candidate to be accepted above the water level")
+	}
+	g.Level = 20.0
+	g.Init(10.0)
+	if !g.Accept(15.0, 10.0, 10.0) {
+		t.Error("expected Init to use the given level")
+	}
+}
+
+func TestLateAcceptance(t *testing.T) {
+	l := &LateAcceptance{}
+	if l.Verify() == nil {
+		t.Error("expected verification to fail with length 0")
+	}
+	l.Length = 2
+	l.Init(10.0)
+	if !l.Accept(5.0, 10.0, 10.0) {
+		t.Error("expected improving candidate to be accepted")
+	}
+	// history is [5, 10], the second slot is compared next
+	if !l.Accept(9.0, 5.0, 5.0) {
+		t.Error("expected candidate not worse than the late solution to be accepted")
+	}
+	// history is [5, 9], the first slot is compared next
+	if l.Accept(9.5, 9.0, 5.0) {
+		t.Error("expected candidate worse than current and late solution to be rejected")
+	}
+}
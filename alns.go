@@ -0,0 +1,223 @@
+package hego
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ALNSState describes a solution during an adaptive large neighborhood search
+type ALNSState interface {
+	// Objective is the function to be minimized. It is only called on repaired solutions
+	Objective() float64
+}
+
+// DestroyOperator removes a part of a solution. It must not modify state, but
+// return a new partial solution
+type DestroyOperator func(state ALNSState) ALNSState
+
+// RepairOperator rebuilds a complete solution from a partial one. It must not
+// modify state, but return a new solution
+type RepairOperator func(state ALNSState) ALNSState
+
+// ALNSResult holds result and progress information about the adaptive large
+// neighborhood search
+type ALNSResult struct {
+	// States holds the best states. Last element in this list is overall best solution
+	States []ALNSState
+	// Objectives holds the best objectives. Each entry corresponds to an element in States
+	Objectives    []float64
+	BestState     ALNSState
+	BestObjective float64
+	// DestroyWeights are the final weights of the destroy operators
+	DestroyWeights []float64
+	// RepairWeights are the final weights of the repair operators
+	RepairWeights []float64
+	Result
+}
+
+// ALNSSettings describes the necessary settings for the adaptive large
+// neighborhood search
+type ALNSSettings struct {
+	// Acceptance decides whether a repaired solution replaces the current one.
+	// When nil, HillClimbing is used
+	Acceptance AcceptanceCriterion
+	// ScoreBest is the score of an operator pair that found a new best solution
+	ScoreBest float64
+	// ScoreBetter is the score of an operator pair that improved the current solution
+	ScoreBetter float64
+	// ScoreAccepted is the score of an operator pair whose worse solution was accepted
+	ScoreAccepted float64
+	// ReactionFactor in (0, 1) controls how fast the operator weights follow the
+	// average scores of the last segment
+	ReactionFactor float64
+	// SegmentLength is the number of iterations between weight updates
+	SegmentLength int
+	// CacheSize is the number of objective values kept in a least recently used
+	// cache. It is only used for states implementing Keyed. 0 disables the cache
+	CacheSize int
+	Settings
+}
+
+// Verify returns an error if settings verification fails
+func (s *ALNSSettings) Verify() error {
+	if s.ScoreBest < 0.0 || s.ScoreBetter < 0.0 || s.ScoreAccepted < 0.0 {
+		return errors.New("scores must not be negative")
+	}
+	if s.ReactionFactor <= 0.0 || s.ReactionFactor >= 1.0 {
+		return fmt.Errorf("reaction factor must be in (0, 1), got %v", s.ReactionFactor)
+	}
+	if s.SegmentLength < 1 {
+		return fmt.Errorf("segment length must be greater than 0, got %v", s.SegmentLength)
+	}
+	if s.CacheSize < 0 {
+		return fmt.Errorf("cache size cannot be negative, got %v", s.CacheSize)
+	}
+	if s.Acceptance != nil {
+		return s.Acceptance.Verify()
+	}
+	return nil
+}
+
+// minOperatorWeight keeps operators without success selectable
+const minOperatorWeight = 1e-3
+
+// operatorStats holds the adaptive weights of a group of operators
+type operatorStats struct {
+	weights []float64
+	scores  []float64
+	uses    []int
+}
+
+// newOperatorStats returns stats for n operators with equal weights
+func newOperatorStats(n int) *operatorStats {
+	o := operatorStats{weights: make([]float64, n), scores: make([]float64, n), uses: make([]int, n)}
+	for i := range o.weights {
+		o.weights[i] = 1.0
+	}
+	return &o
+}
+
+// choose selects an operator proportional to its weight
+func (o *operatorStats) choose() int {
+	return weightedChoice(o.weights, 1)[0]
+}
+
+// reward adds score to the operator
+func (o *operatorStats) reward(i int, score float64) {
+	o.scores[i] += score
+	o.uses[i]++
+}
+
+// update moves the weights towards the average scores of the last segment and
+// resets the scores. Unused operators keep their weight
+func (o *operatorStats) update(reaction float64) {
+	for i := range o.weights {
+		if o.uses[i] > 0 {
+			o.weights[i] = math.Max(minOperatorWeight, (1.0-reaction)*o.weights[i]+reaction*o.scores[i]/float64(o.uses[i]))
+		}
+		o.scores[i], o.uses[i] = 0.0, 0
+	}
+}
+
+// ALNS performs adaptive large neighborhood search. Every iteration destroys
+// and repairs the current solution with operators chosen proportional to their
+// weights. The new solution is accepted by the acceptance criterion and the
+// operators are scored by the outcome. After every segment the operator
+// weights are adapted to the average scores
+func ALNS(
+	initialState ALNSState,
+	destroy []DestroyOperator,
+	repair []RepairOperator,
+	settings ALNSSettings,
+) (res ALNSResult, err error) {
+
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return
+	}
+	if len(destroy) == 0 || len(repair) == 0 {
+		err = errors.New("at least one destroy and one repair operator are required")
+		return
+	}
+
+	start := time.Now()
+
+	logger := newLogger("Adaptive Large Neighborhood Search", []string{"Iteration", "Objective", "Best"}, settings.Verbose, settings.MaxIterations)
+
+	cache := newEvalCache(settings.CacheSize)
+	evaluate := func(s ALNSState) float64 {
+		if obj, ok := cache.lookup(s); ok {
+			res.CacheHits++
+			return obj
+		}
+		res.FuncEvaluations++
+		obj := s.Objective()
+		cache.store(s, obj)
+		return obj
+	}
+	acceptance := settings.Acceptance
+	if acceptance == nil {
+		acceptance = &HillClimbing{}
+	}
+
+	destroyStats, repairStats := newOperatorStats(len(destroy)), newOperatorStats(len(repair))
+
+	if settings.KeepHistory {
+		res.States = make([]ALNSState, 0, settings.MaxIterations)
+		res.Objectives = make([]float64, 0, settings.MaxIterations)
+	}
+
+	state, obj := initialState, evaluate(initialState)
+	res.BestState, res.BestObjective = state, obj
+	acceptance.Init(obj)
+
+	for i := 0; i < settings.MaxIterations; i++ {
+		d, r := destroyStats.choose(), repairStats.choose()
+		candidate := repair[r](destroy[d](state))
+		candidateObj := evaluate(candidate)
+
+		score := 0.0
+		if acceptance.Accept(candidateObj, obj, res.BestObjective) {
+			switch {
+			case candidateObj < res.BestObjective:
+				score = settings.ScoreBest
+				res.BestState, res.BestObjective = candidate, candidateObj
+				if settings.KeepHistory {
+					res.States = append(res.States, candidate)
+					res.Objectives = append(res.Objectives, candidateObj)
+				}
+			case candidateObj < obj:
+				score = settings.ScoreBetter
+			default:
+				score = settings.ScoreAccepted
+			}
+			state, obj = candidate, candidateObj
+		}
+		destroyStats.reward(d, score)
+		repairStats.reward(r, score)
+		if (i+1)%settings.SegmentLength == 0 {
+			destroyStats.update(settings.ReactionFactor)
+			repairStats.update(settings.ReactionFactor)
+		}
+
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(obj),
+			fmt.Sprint(res.BestObjective),
+		})
+	}
+	res.DestroyWeights = destroyStats.weights
+	res.RepairWeights = repairStats.weights
+
+	res.Runtime = time.Since(start)
+	res.Iterations = settings.MaxIterations
+
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return
+}
@@ -0,0 +1,143 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// alnsTour is a closed tour starting at city 0. Destroyed cities are kept in removed
+type alnsTour struct {
+	tour     []int
+	removed  []int
+	distance func(i, j int) float64
+}
+
+func (a alnsTour) Objective() float64 {
+	return TourLength(append(append([]int{}, a.tour...), a.tour[0]), a.distance)
+}
+
+// randomRemoval removes three random cities except the first one
+func randomRemoval(s ALNSState) ALNSState {
+	a := s.(alnsTour)
+	tour := append([]int{}, a.tour...)
+	removed := []int{}
+	for i := 0; i < 3; i++ {
+		k := 1 + rand.Intn(len(tour)-1)
+		removed = append(removed, tour[k])
+		tour = append(tour[:k], tour[k+1:]...)
+	}
+	return alnsTour{tour: tour, removed: removed, distance: a.distance}
+}
+
+// greedyInsertion inserts every removed city at its cheapest position
+func greedyInsertion(s ALNSState) ALNSState {
+	a := s.(alnsTour)
+	tour := append([]int{}, a.tour...)
+	for _, c := range a.removed {
+		best, bestCost := 1, math.MaxFloat64
+		for k := 1; k <= len(tour); k++ {
+			prev, next := tour[k-1], tour[k%len(tour)]
+			if cost := a.distance(prev, c) + a.distance(c, next) - a.distance(prev, next); cost < bestCost {
+				best, bestCost = k, cost
+			}
+		}
+		tour = append(tour[:best], append([]int{c}, tour[best:]...)...)
+	}
+	return alnsTour{tour: tour, distance: a.distance}
+}
+
+// randomInsertion inserts every removed city at a random position
+func randomInsertion(s ALNSState) ALNSState {
+	a := s.(alnsTour)
+	tour := append([]int{}, a.tour...)
+	for _, c := range a.removed {
+		k := 1 + rand.Intn(len(tour))
+		tour = append(tour[:k], append([]int{c}, tour[k:]...)...)
+	}
+	return alnsTour{tour: tour, distance: a.distance}
+}
+
+func TestVerifyALNSSettings(t *testing.T) {
+	settings := ALNSSettings{ScoreBest: -1.0}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with negative score")
+	}
+	settings.ScoreBest = 10.0
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with reaction factor 0")
+	}
+	settings.ReactionFactor = 0.5
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with segment length 0")
+	}
+	settings.SegmentLength = 10
+	settings.Acceptance = &AnnealingAcceptance{}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with invalid acceptance criterion")
+	}
+	settings.Acceptance = &RecordToRecord{Deviation: 1.0}
+	if err := settings.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+}
+
+func TestOperatorStats(t *testing.T) {
+	o := newOperatorStats(2)
+	o.reward(0, 10.0)
+	o.reward(0, 0.0)
+	o.reward(1, 0.0)
+	o.update(0.5)
+	if o.weights[0] != 3.0 || o.weights[1] != 0.5 {
+		t.Errorf("expected weights [3, 0.5], got %v", o.weights)
+	}
+	for i := 0; i < 100; i++ {
+		o.reward(1, 0.0)
+		o.update(0.5)
+	}
+	if o.weights[1] != minOperatorWeight {
+		t.Errorf("expected weight to stay at the minimum %v, got %v", minOperatorWeight, o.weights[1])
+	}
+}
+
+func TestALNS(t *testing.T) {
+	n := 8
+	distance := randomPoints(n)
+	optimum := bruteForceTour(n, distance)
+	initialState := alnsTour{tour: append([]int{0}, rand.Perm(n-1)...), distance: distance}
+	for i := 1; i < n; i++ {
+		initialState.tour[i]++
+	}
+	destroy := []DestroyOperator{randomRemoval}
+	repair := []RepairOperator{greedyInsertion, randomInsertion}
+
+	settings := ALNSSettings{}
+	_, err := ALNS(initialState, destroy, repair, settings)
+	if err == nil {
+		t.Error("ALNS should fail with invalid settings")
+	}
+	settings.ScoreBest = 10.0
+	settings.ScoreBetter = 5.0
+	settings.ScoreAccepted = 1.0
+	settings.ReactionFactor = 0.2
+	settings.SegmentLength = 20
+	settings.MaxIterations = 1000
+	settings.KeepHistory = true
+	_, err = ALNS(initialState, nil, repair, settings)
+	if err == nil {
+		t.Error("ALNS should fail without destroy operators")
+	}
+	for _, acceptance := range []AcceptanceCriterion{nil, &AnnealingAcceptance{Temperature: 0.1, AnnealingFactor: 0.995}, &RecordToRecord{Deviation: 0.05}} {
+		settings.Acceptance = acceptance
+		res, err := ALNS(initialState, destroy, repair, settings)
+		if err != nil {
+			t.Errorf("Error while running ALNS: %v", err)
+		}
+		if math.Abs(res.BestObjective-optimum) > 1e-9 {
+			t.Errorf("ALNS with acceptance %T did not find the optimal tour %v, got %v", acceptance, optimum, res.BestObjective)
+		}
+		if len(res.RepairWeights) != 2 || res.RepairWeights[0] <= res.RepairWeights[1] {
+			t.Errorf("expected greedy insertion to gain a higher weight than random insertion, got %v", res.RepairWeights)
+		}
+	}
+}
@@ -15,12 +15,17 @@ type AnnealingState interface {
 }
 
 // SAResult represents the result of the Anneal optimization. The last state
-// and last energy are the final results. It extends the basic Result type
+// and last energy are the final results. It extends the basic Result type.
+// GD, RRT and LAHC return the same result type
 type SAResult struct {
 	// State is the result state
 	State AnnealingState
 	// Energy is the result Energy
 	Energy float64
+	// BestState is the state with the lowest energy visited during the process
+	BestState AnnealingState
+	// BestEnergy is the energy of BestState
+	BestEnergy float64
 	// States when KeepIntermediateResults is set hold every state during the
 	// process (updated on state change)
 	States []AnnealingState
@@ -44,6 +49,9 @@ type SASettings struct {
 	// iterations convergence might be reached. It's good to reach low temperatures
 	// during the last third of iterations
 	AnnealingFactor float64
+	// CacheSize is the number of energies kept in a least recently used cache.
+	// It is only used for states implementing Keyed. 0 disables the cache
+	CacheSize int
 	Settings
 }
 
@@ -55,6 +63,9 @@ func (s *SASettings) Verify() error {
 	if s.AnnealingFactor > 1.0 || s.AnnealingFactor <= 0.0 {
 		return fmt.Errorf("annealing factor must be between 0.0 and 1.0, got %v", s.AnnealingFactor)
 	}
+	if s.CacheSize < 0 {
+		return fmt.Errorf("cache size cannot be negative, got %v", s.CacheSize)
+	}
 	return nil
 }
 
@@ -74,13 +85,21 @@ func SA(
 
 	logger := newLogger("Simulated Annealing", []string{"Iteration", "Temperature", "Energy"}, settings.Verbose, settings.MaxIterations)
 
+	cache := newEvalCache(settings.CacheSize)
 	evaluate := func(s AnnealingState) float64 {
+		if energy, ok := cache.lookup(s); ok {
+			res.CacheHits++
+			return energy
+		}
 		res.FuncEvaluations++
-		return s.Energy()
+		energy := s.Energy()
+		cache.store(s, energy)
+		return energy
 	}
 
 	state := initialState
 	energy := evaluate(state)
+	res.BestState, res.BestEnergy = state, energy
 	temperature := settings.Temperature
 
 	if settings.KeepHistory {
@@ -100,6 +119,9 @@ func SA(
 		if update {
 			state = candidate
 			energy = candidateEnergy
+			if energy < res.BestEnergy {
+				res.BestState, res.BestEnergy = state, energy
+			}
 			if settings.KeepHistory {
 				res.States = append(res.States, candidate)
 				res.Energies = append(res.Energies, candidateEnergy)
@@ -1,6 +1,7 @@
 package hego
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 	"testing"
@@ -66,4 +67,39 @@ func TestSA(t *testing.T) {
 	if math.Abs(res.Energy) > 0.5 {
 		t.Error("unexpected solution")
 	}
+	if res.BestEnergy > res.Energy || res.BestState.Energy() != res.BestEnergy {
+		t.Errorf("best energy %v should belong to the best state and not exceed %v", res.BestEnergy, res.Energy)
+	}
+}
+
+type keyedState int
+
+func (s keyedState) Energy() float64 {
+	return float64(s * s)
+}
+
+func (s keyedState) Neighbor() AnnealingState {
+	return s + keyedState(rand.Intn(3)-1)
+}
+
+func (s keyedState) Key() string {
+	return fmt.Sprint(int(s))
+}
+
+func TestSACache(t *testing.T) {
+	settings := SASettings{}
+	settings.Temperature = 10.0
+	settings.AnnealingFactor = 0.99
+	settings.MaxIterations = 1000
+	settings.CacheSize = 10
+	res, err := SA(keyedState(10), settings)
+	if err != nil {
+		t.Errorf("Error while running SA with cache: %v", err)
+	}
+	if res.CacheHits == 0 {
+		t.Error("expected cache hits for repeated integer states")
+	}
+	if res.CacheHits+res.FuncEvaluations != settings.MaxIterations+1 {
+		t.Errorf("cache hits and evaluations should add up to the number of energy calls, got %v + %v", res.CacheHits, res.FuncEvaluations)
+	}
 }
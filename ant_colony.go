@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/rand"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -23,17 +26,120 @@ type Ant interface {
 	Performance() float64
 }
 
+// TrailAnt is an Ant that allows ACO to manipulate the pheromone trails
+// directly. MaxMinAntSystem and AntColonySystem require ants implementing it
+type TrailAnt interface {
+	Ant
+	// LimitPheromone restricts every trail to [min, max]. The limits are computed
+	// for ants dropping 1 / performance, ants dropping other amounts should
+	// scale them accordingly
+	LimitPheromone(min, max float64)
+	// ResetPheromone sets every trail to value, scaled like in LimitPheromone
+	ResetPheromone(value float64)
+	// LocalUpdate pulls the trail of the edge traversed in the last step
+	// towards initial: trail = (1 - evaporation) * trail + evaporation * initial
+	LocalUpdate(evaporation, initial float64)
+}
+
+// ClonableAnt is an Ant that can be copied. ACO reuses the ants in every
+// iteration, which overwrites their tours. For ants implementing ClonableAnt,
+// ACO records copies of the best ants, which keep their tours after the run.
+// Deposit strategies reinforcing the best-so-far tour require ClonableAnt
+type ClonableAnt interface {
+	Ant
+	// Clone returns an independent copy of the ant including its current tour
+	Clone() Ant
+}
+
+// ImprovableAnt is an Ant that improves its tour by local search, e.g. with
+// TwoOpt or OrOpt. ACO calls Improve after the tour is constructed and before
+// it is evaluated, so the improved tour is used for the pheromone deposit
+type ImprovableAnt interface {
+	Ant
+	// Improve applies local search to the current tour
+	Improve()
+}
+
+// DeadEndAnt is an Ant that is notified when it cannot continue its tour. It
+// is required by the ReportDeadEnd policy
+type DeadEndAnt interface {
+	Ant
+	// DeadEnd is called instead of Step when PerceivePheromone returned no
+	// option with pheromone. The tour ends and Performance should report the
+	// infeasibility, e.g. by a penalty
+	DeadEnd()
+}
+
+// DeadEndPolicy encodes how ACO handles ants that cannot continue their tour,
+// because PerceivePheromone returned no option with pheromone. Ants that
+// reached a dead end never drop pheromone
+type DeadEndPolicy int
+
+const (
+	// AbortTour ends the tour of the ant without evaluating it, its performance
+	// is +Inf
+	AbortTour DeadEndPolicy = iota
+	// RestartTour initializes the ant again and lets it start over. After
+	// MaxRestarts restarts the tour is aborted like with AbortTour
+	RestartTour
+	// ReportDeadEnd ends the tour and notifies the ant by calling DeadEnd. The
+	// ant is evaluated and reports the infeasibility through its performance
+	ReportDeadEnd
+)
+
+// DepositStrategy encodes which ants drop pheromone after an iteration.
+// Strategies weighting the deposit call DropPheromone(performance / weight),
+// which assumes ants dropping pheromone inversely proportional to performance
+type DepositStrategy int
+
+const (
+	// IterationBestDeposit lets the best ant of the iteration drop pheromone
+	IterationBestDeposit DepositStrategy = iota
+	// AllAntsDeposit lets every ant drop pheromone (Ant System)
+	AllAntsDeposit
+	// GlobalBestDeposit lets the best-so-far ant drop pheromone
+	GlobalBestDeposit
+	// RankBasedDeposit lets the best RankSize - 1 ants of the iteration drop
+	// pheromone weighted by RankSize - rank, the best-so-far ant drops pheromone
+	// weighted by RankSize
+	RankBasedDeposit
+	// ElitistDeposit lets every ant drop pheromone and the best-so-far ant
+	// drops additional pheromone weighted by ElitistWeight
+	ElitistDeposit
+)
+
+// ACOVariant encodes different variants of the ant colony optimization
+type ACOVariant int
+
+const (
+	// AntSystem is the basic ant colony optimization
+	AntSystem ACOVariant = iota
+	// MaxMinAntSystem (MMAS) restricts trails to [max / TrailRatio, max], where
+	// the upper limit max = 1 / ((1 - Evaporation) * best performance) is derived
+	// from the best solution found so far. All trails are reinitialized to max
+	// when no improvement was found for StagnationLimit iterations
+	MaxMinAntSystem
+	// AntColonySystem (ACS) uses the pseudo-random proportional rule: with
+	// probability Exploitation an ant takes the option with the most pheromone,
+	// otherwise it makes a weighted random choice. After every step, the trail
+	// of the traversed edge is pulled towards InitialPheromone (local update),
+	// which makes other ants explore different edges
+	AntColonySystem
+)
+
 // ACOResult represents the result of the ACO
 type ACOResult struct {
 	// AveragePerformances holds the mean performances for each iteration
 	AveragePerformances []float64
 	// BestPerformances holds the best performance for each iteration
 	BestPerformances []float64
-	// BestAnts holds the best Ant for each iteration
+	// BestAnts holds the best Ant for each iteration. Only ants implementing
+	// ClonableAnt are copies that keep the tour of that iteration
 	BestAnts []Ant
 	// BestPerformance stores the overall best ants performance
 	BestPerformance float64
-	// BestAnt stores the overall best ant
+	// BestAnt stores the overall best ant. Only ants implementing ClonableAnt
+	// are copies that keep the best tour after the run
 	BestAnt Ant
 	Result
 }
@@ -44,6 +150,38 @@ type ACOSettings struct {
 	Evaporation float64
 	// MinPheromone is the lowest possible amount of pheromone. Convergence to the true optimum is theoretically only guaranteed for a minpheromone > 0
 	MinPheromone float64
+	// Variant selects the ant colony optimization variant
+	Variant ACOVariant
+	// TrailRatio is the ratio of upper and lower trail limit in MaxMinAntSystem
+	TrailRatio float64
+	// StagnationLimit is the number of iterations without improvement after which
+	// MaxMinAntSystem reinitializes the trails. 0 disables the reinitialization
+	StagnationLimit int
+	// Exploitation is the probability of an ant in AntColonySystem to choose the
+	// option with the most pheromone instead of a weighted random choice
+	Exploitation float64
+	// LocalEvaporation must be a value in (0, 1] and is the strength of the local
+	// pheromone update in AntColonySystem
+	LocalEvaporation float64
+	// InitialPheromone is the value trails are pulled towards by the local
+	// pheromone update in AntColonySystem
+	InitialPheromone float64
+	// DepositStrategy defines which ants drop pheromone after each iteration
+	DepositStrategy DepositStrategy
+	// RankSize is the number of weighted ants for RankBasedDeposit
+	RankSize int
+	// ElitistWeight is the weight of the best-so-far ant for ElitistDeposit
+	ElitistWeight float64
+	// Workers is the number of goroutines constructing tours concurrently. Trails
+	// are only read during construction, deposit and evaporation are applied
+	// after all tours are finished. The Ant methods Init, Step, PerceivePheromone,
+	// Improve and Performance must be safe for concurrent use on different ants.
+	// 0 or 1 constructs tours sequentially
+	Workers int
+	// DeadEndPolicy defines how ants that cannot continue their tour are handled
+	DeadEndPolicy DeadEndPolicy
+	// MaxRestarts is the number of restarts of an ant per iteration with RestartTour
+	MaxRestarts int
 	Settings
 }
 
@@ -52,9 +190,200 @@ func (s *ACOSettings) Verify() error {
 	if s.Evaporation <= 0 || s.Evaporation > 1 {
 		return errors.New("evaporation must be a value in (0, 1]")
 	}
+	switch s.Variant {
+	case AntSystem:
+	case MaxMinAntSystem:
+		if s.Evaporation == 1 {
+			return errors.New("MaxMinAntSystem requires evaporation below 1")
+		}
+		if s.TrailRatio <= 1 {
+			return fmt.Errorf("trail ratio must be greater than 1, got %v", s.TrailRatio)
+		}
+		if s.StagnationLimit < 0 {
+			return fmt.Errorf("stagnation limit cannot be negative, got %v", s.StagnationLimit)
+		}
+	case AntColonySystem:
+		if s.Exploitation < 0 || s.Exploitation > 1 {
+			return fmt.Errorf("exploitation must be a value in [0, 1], got %v", s.Exploitation)
+		}
+		if s.LocalEvaporation <= 0 || s.LocalEvaporation > 1 {
+			return errors.New("local evaporation must be a value in (0, 1]")
+		}
+		if s.InitialPheromone <= 0 {
+			return fmt.Errorf("initial pheromone must be greater than 0, got %v", s.InitialPheromone)
+		}
+	default:
+		return fmt.Errorf("unknown ACO variant %v", s.Variant)
+	}
+	if s.DeadEndPolicy < AbortTour || s.DeadEndPolicy > ReportDeadEnd {
+		return fmt.Errorf("unknown dead end policy %v", s.DeadEndPolicy)
+	}
+	if s.MaxRestarts < 0 {
+		return fmt.Errorf("max restarts cannot be negative, got %v", s.MaxRestarts)
+	}
+	if s.Workers < 0 {
+		return fmt.Errorf("number of workers cannot be negative, got %v", s.Workers)
+	}
+	if s.Workers > 1 && s.Variant == AntColonySystem {
+		return errors.New("AntColonySystem updates trails during construction and cannot construct tours concurrently")
+	}
+	switch s.DepositStrategy {
+	case IterationBestDeposit, AllAntsDeposit, GlobalBestDeposit:
+	case RankBasedDeposit:
+		if s.RankSize < 2 {
+			return fmt.Errorf("rank size must be at least 2, got %v", s.RankSize)
+		}
+	case ElitistDeposit:
+		if s.ElitistWeight <= 0 {
+			return fmt.Errorf("elitist weight must be greater than 0, got %v", s.ElitistWeight)
+		}
+	default:
+		return fmt.Errorf("unknown deposit strategy %v", s.DepositStrategy)
+	}
 	return nil
 }
 
+// runAnt constructs and improves the tour of the ant and returns its
+// performance and whether the tour was completed. Dead ends are handled as
+// defined by the DeadEndPolicy
+func runAnt(ant Ant, settings *ACOSettings) (performance float64, completed bool) {
+	for restarts := 0; !constructTour(ant, settings); restarts++ {
+		switch settings.DeadEndPolicy {
+		case RestartTour:
+			if restarts < settings.MaxRestarts {
+				continue
+			}
+		case ReportDeadEnd:
+			ant.(DeadEndAnt).DeadEnd()
+			return ant.Performance(), false
+		}
+		return math.Inf(1), false
+	}
+	if improvable, ok := ant.(ImprovableAnt); ok {
+		improvable.Improve()
+	}
+	return ant.Performance(), true
+}
+
+// buildTours lets every ant construct a tour and stores its performance and
+// whether it completed the tour. With more than one worker the tours are
+// constructed concurrently
+func buildTours(population []Ant, performances []float64, completed []bool, settings *ACOSettings) {
+	if settings.Workers <= 1 {
+		for i, ant := range population {
+			performances[i], completed[i] = runAnt(ant, settings)
+		}
+		return
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < settings.Workers && w < len(population); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				performances[i], completed[i] = runAnt(population[i], settings)
+			}
+		}()
+	}
+	for i := range population {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// snapshot returns a copy of the ant if it implements ClonableAnt, otherwise
+// the ant itself
+func snapshot(a Ant) Ant {
+	if c, ok := a.(ClonableAnt); ok {
+		return c.Clone()
+	}
+	return a
+}
+
+// needsGlobalBest returns true if the deposit strategy uses the best-so-far ant
+func (s *ACOSettings) needsGlobalBest() bool {
+	return s.DepositStrategy == GlobalBestDeposit || s.DepositStrategy == RankBasedDeposit || s.DepositStrategy == ElitistDeposit
+}
+
+// depositPheromone lets the ants selected by the deposit strategy drop
+// pheromone. Only ants that completed their tour are considered. globalBest is
+// a copy of the best-so-far ant, which is only used if the strategy needs it
+func depositPheromone(population []Ant, performances []float64, completed []bool, bestIndex int, globalBest Ant, globalBestPerformance float64, settings *ACOSettings) {
+	switch settings.DepositStrategy {
+	case IterationBestDeposit:
+		population[bestIndex].DropPheromone(performances[bestIndex])
+	case AllAntsDeposit:
+		for i, ant := range population {
+			if completed[i] {
+				ant.DropPheromone(performances[i])
+			}
+		}
+	case GlobalBestDeposit:
+		globalBest.DropPheromone(globalBestPerformance)
+	case RankBasedDeposit:
+		ranks := make([]int, 0, len(population))
+		for i := range population {
+			if completed[i] {
+				ranks = append(ranks, i)
+			}
+		}
+		sort.Slice(ranks, func(a, b int) bool {
+			return performances[ranks[a]] < performances[ranks[b]]
+		})
+		for rank := 1; rank < settings.RankSize && rank <= len(ranks); rank++ {
+			index := ranks[rank-1]
+			weight := float64(settings.RankSize - rank)
+			population[index].DropPheromone(performances[index] / weight)
+		}
+		globalBest.DropPheromone(globalBestPerformance / float64(settings.RankSize))
+	case ElitistDeposit:
+		for i, ant := range population {
+			if completed[i] {
+				ant.DropPheromone(performances[i])
+			}
+		}
+		globalBest.DropPheromone(globalBestPerformance / settings.ElitistWeight)
+	}
+}
+
+// argMax returns the index of the largest value, -1 if all values are 0
+func argMax(values []float64) int {
+	best := -1
+	for i, v := range values {
+		if v > 0.0 && (best == -1 || v > values[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// constructTour lets the ant create a path with the decision rule of the
+// selected variant. Returns false if the ant reached a dead end
+func constructTour(ant Ant, settings *ACOSettings) bool {
+	ant.Init()
+	for {
+		options := ant.PerceivePheromone()
+		var next int
+		if settings.Variant == AntColonySystem && rand.Float64() < settings.Exploitation {
+			next = argMax(options)
+		} else {
+			next = weightedChoice(options, 1)[0]
+		}
+		if next == -1 {
+			return false
+		}
+		done := ant.Step(next) // step returns true when ant is done
+		if settings.Variant == AntColonySystem {
+			ant.(TrailAnt).LocalUpdate(settings.LocalEvaporation, settings.InitialPheromone)
+		}
+		if done {
+			return true
+		}
+	}
+}
+
 // ACO performs the ant colony optimization algorithm
 func ACO(population []Ant, settings ACOSettings) (res ACOResult, err error) {
 	err = settings.Verify()
@@ -62,15 +391,31 @@ func ACO(population []Ant, settings ACOSettings) (res ACOResult, err error) {
 		err = fmt.Errorf("settings verifycation failed: %v", err)
 		return
 	}
+	if len(population) == 0 {
+		err = errors.New("population must not be empty")
+		return
+	}
+	if settings.Variant != AntSystem {
+		if _, ok := population[0].(TrailAnt); !ok {
+			err = errors.New("MaxMinAntSystem and AntColonySystem require ants implementing TrailAnt")
+			return
+		}
+	}
+	if settings.DeadEndPolicy == ReportDeadEnd {
+		if _, ok := population[0].(DeadEndAnt); !ok {
+			err = errors.New("ReportDeadEnd requires ants implementing DeadEndAnt")
+			return
+		}
+	}
+	if settings.needsGlobalBest() {
+		if _, ok := population[0].(ClonableAnt); !ok {
+			err = errors.New("global best, rank based and elitist deposit require ants implementing ClonableAnt")
+			return
+		}
+	}
 
 	start := time.Now()
 
-	// increase FuncEvaluations for every performance call
-	evaluate := func(a Ant) float64 {
-		res.FuncEvaluations++
-		return a.Performance()
-	}
-
 	logger := newLogger("Ant Colony Optimization", []string{"Iteration", "Average Performance", "Best Performance"}, settings.Verbose, settings.MaxIterations)
 
 	if settings.KeepHistory {
@@ -80,49 +425,76 @@ func ACO(population []Ant, settings ACOSettings) (res ACOResult, err error) {
 	}
 
 	res.BestPerformance = math.MaxFloat64
+	// stagnation counts the iterations without improvement for MaxMinAntSystem
+	stagnation := 0
+	performances := make([]float64, len(population))
+	completed := make([]bool, len(population))
 
 	for i := 0; i < settings.MaxIterations; i++ {
 		totalPerformance := 0.0
 		bestPerformance := math.MaxFloat64
 		bestIndex := -1
-		for antIndex, ant := range population {
-			// initialize ant
-			ant.Init()
-			// create path for this ant
-			for {
-				options := ant.PerceivePheromone()
-				next := weightedChoice(options, 1)[0]
-				if ant.Step(next) { // step returns true when ant is done
-					break
-				}
+		completedTours := 0
+		// create and evaluate a path for every ant
+		buildTours(population, performances, completed, &settings)
+		for antIndex, performance := range performances {
+			if completed[antIndex] || settings.DeadEndPolicy == ReportDeadEnd {
+				res.FuncEvaluations++
+			}
+			if !completed[antIndex] {
+				continue
 			}
-			// evaluate path
-			performance := evaluate(ant)
+			completedTours++
 			totalPerformance += performance
 			if performance < bestPerformance {
 				bestPerformance = performance
 				bestIndex = antIndex
 			}
 		}
-		population[bestIndex].DropPheromone(bestPerformance)
-		// population[bestIndex].DropPheromone(bestPerformance)
-		population[0].Evaporate(settings.Evaporation, settings.MinPheromone)
+		if completedTours == 0 {
+			err = fmt.Errorf("no ant completed a tour in iteration %v", i)
+			break
+		}
+		averagePerformance := totalPerformance / float64(completedTours)
 
 		if settings.KeepHistory {
-			res.AveragePerformances[i] = totalPerformance / float64(len(population))
+			res.AveragePerformances[i] = averagePerformance
 			res.BestPerformances[i] = bestPerformance
-			res.BestAnts[i] = population[bestIndex]
+			res.BestAnts[i] = snapshot(population[bestIndex])
 		}
 
 		if res.BestPerformance > bestPerformance {
 			res.BestPerformance = bestPerformance
-			res.BestAnt = population[bestIndex]
+			res.BestAnt = snapshot(population[bestIndex])
+			stagnation = 0
+		} else {
+			stagnation++
+		}
+
+		// the trail limits of MaxMinAntSystem are derived from the best performance
+		if settings.Variant == MaxMinAntSystem && res.BestPerformance <= 0 {
+			err = fmt.Errorf("MaxMinAntSystem requires positive performances, got %v", res.BestPerformance)
+			break
+		}
+
+		depositPheromone(population, performances, completed, bestIndex, res.BestAnt, res.BestPerformance, &settings)
+		population[0].Evaporate(settings.Evaporation, settings.MinPheromone)
+
+		if settings.Variant == MaxMinAntSystem {
+			trailAnt := population[0].(TrailAnt)
+			maxTrail := 1 / ((1 - settings.Evaporation) * res.BestPerformance)
+			if settings.StagnationLimit > 0 && stagnation >= settings.StagnationLimit {
+				trailAnt.ResetPheromone(maxTrail)
+				stagnation = 0
+			} else {
+				trailAnt.LimitPheromone(maxTrail/settings.TrailRatio, maxTrail)
+			}
 		}
 
 		res.Iterations++
 		logger.AddLine(i, []string{
 			fmt.Sprint(i),
-			fmt.Sprint(totalPerformance / float64(len(population))),
+			fmt.Sprint(averagePerformance),
 			fmt.Sprint(bestPerformance),
 		})
 	}
@@ -1,6 +1,9 @@
 package hego
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 type ant []bool
 
@@ -56,3 +59,344 @@ func TestACO(t *testing.T) {
 		t.Error("best ant should have same performance as any other ant")
 	}
 }
+
+func TestVerifyACOVariants(t *testing.T) {
+	settings := ACOSettings{}
+	settings.Evaporation = 0.9
+	settings.Variant = MaxMinAntSystem
+	if settings.Verify() == nil {
+		t.Error("verification should fail for MMAS without trail ratio")
+	}
+	settings.TrailRatio = 10
+	if err := settings.Verify(); err != nil {
+		t.Errorf("verification should pass for valid MMAS settings, got %v", err)
+	}
+	settings.Evaporation = 1.0
+	if settings.Verify() == nil {
+		t.Error("verification should fail for MMAS without evaporation")
+	}
+	settings.Evaporation = 0.9
+	settings.Variant = AntColonySystem
+	settings.Exploitation = 1.1
+	if settings.Verify() == nil {
+		t.Error("verification should fail for exploitation above 1")
+	}
+	settings.Exploitation = 0.9
+	settings.LocalEvaporation = 0.1
+	if settings.Verify() == nil {
+		t.Error("verification should fail for ACS without initial pheromone")
+	}
+	settings.InitialPheromone = 0.01
+	if err := settings.Verify(); err != nil {
+		t.Errorf("verification should pass for valid ACS settings, got %v", err)
+	}
+	settings.Variant = ACOVariant(10)
+	if settings.Verify() == nil {
+		t.Error("verification should fail for unknown variant")
+	}
+}
+
+func TestArgMax(t *testing.T) {
+	if argMax([]float64{0.1, 0.3, 0.2}) != 1 {
+		t.Error("expected index 1 to have the largest value")
+	}
+	if argMax([]float64{0.0, 0.0}) != -1 {
+		t.Error("expected -1 when all values are 0")
+	}
+}
+
+func TestACOVariants(t *testing.T) {
+	settings := ACOSettings{}
+	settings.Evaporation = 0.9
+	settings.MinPheromone = 0.01
+	settings.MaxIterations = 50
+	settings.TrailRatio = 20
+	settings.StagnationLimit = 10
+	settings.Exploitation = 0.9
+	settings.LocalEvaporation = 0.1
+	settings.InitialPheromone = 0.01
+	settings.Variant = MaxMinAntSystem
+	_, err := ACO([]Ant{ant{true}}, settings)
+	if err == nil {
+		t.Error("MMAS should fail for ants not implementing TrailAnt")
+	}
+
+	n := 8
+	distance := circle(n)
+	optimum := float64(n) * distance(0, 1)
+	for _, variant := range []ACOVariant{MaxMinAntSystem, AntColonySystem} {
+		settings.Variant = variant
+		pheromones := NewDensePheromones(n, 1.0)
+		population := make([]Ant, 10)
+		for i := range population {
+			population[i] = NewGraphAnt(pheromones, distance)
+		}
+		res, err := ACO(population, settings)
+		if err != nil {
+			t.Errorf("ACO variant %v should not fail, got: %v", variant, err)
+		}
+		if res.BestPerformance > optimum+1e-9 {
+			t.Errorf("expected optimal tour length %v for variant %v, got %v", optimum, variant, res.BestPerformance)
+		}
+		if settings.Variant == MaxMinAntSystem {
+			maxTrail := 1 / ((1 - settings.Evaporation) * res.BestPerformance)
+			if pheromones.Get(0, 1) > maxTrail+1e-9 || pheromones.Get(0, 4) < maxTrail/settings.TrailRatio-1e-9 {
+				t.Errorf("expected trails within [%v, %v], got %v and %v", maxTrail/settings.TrailRatio, maxTrail, pheromones.Get(0, 1), pheromones.Get(0, 4))
+			}
+		}
+	}
+}
+
+// negativeAnt reports negative tour lengths, which MaxMinAntSystem rejects
+type negativeAnt struct {
+	*GraphAnt
+}
+
+func (a negativeAnt) Performance() float64 { return -a.GraphAnt.Performance() }
+
+func TestACONonPositivePerformance(t *testing.T) {
+	settings := ACOSettings{}
+	settings.Evaporation = 0.9
+	settings.MaxIterations = 10
+	settings.TrailRatio = 20
+	settings.Variant = MaxMinAntSystem
+	pheromones := NewDensePheromones(4, 1.0)
+	population := make([]Ant, 5)
+	for i := range population {
+		population[i] = negativeAnt{NewGraphAnt(pheromones, circle(4))}
+	}
+	res, err := ACO(population, settings)
+	if err == nil {
+		t.Error("MMAS should fail for negative performances")
+	}
+	if pheromones.Get(0, 1) != 1.0 {
+		t.Errorf("expected no pheromone update before the error, got %v", pheromones.Get(0, 1))
+	}
+	if res.Iterations != 0 || res.Runtime == 0 {
+		t.Errorf("expected runtime to be recorded and no completed iteration, got %v iterations in %v", res.Iterations, res.Runtime)
+	}
+}
+
+// recordingAnt counts the pheromone it drops
+type recordingAnt struct {
+	performance float64
+	dropped     *float64
+}
+
+func (a *recordingAnt) Performance() float64              { return a.performance }
+func (a *recordingAnt) DropPheromone(performance float64) { *a.dropped += 1 / performance }
+func (a *recordingAnt) PerceivePheromone() []float64      { return []float64{1.0} }
+func (a *recordingAnt) Evaporate(factor, min float64)     {}
+func (a *recordingAnt) Step(next int) bool                { return true }
+func (a *recordingAnt) Init()                             {}
+func (a *recordingAnt) Clone() Ant                        { clone := *a; return &clone }
+
+func TestDepositPheromone(t *testing.T) {
+	dropped := 0.0
+	population := []Ant{
+		&recordingAnt{performance: 1.0, dropped: &dropped},
+		&recordingAnt{performance: 2.0, dropped: &dropped},
+		&recordingAnt{performance: 4.0, dropped: &dropped},
+	}
+	performances := []float64{1.0, 2.0, 4.0}
+	globalBest := &recordingAnt{performance: 0.5, dropped: &dropped}
+	expectations := map[DepositStrategy]float64{
+		IterationBestDeposit: 1.0,
+		AllAntsDeposit:       1.75,
+		GlobalBestDeposit:    2.0,
+		// ranks 1 and 2 with weights 2 and 1, global best with weight 3
+		RankBasedDeposit: 2.0 + 0.5 + 6.0,
+		// all ants and global best with weight 2
+		ElitistDeposit: 1.75 + 4.0,
+	}
+	settings := ACOSettings{RankSize: 3, ElitistWeight: 2.0}
+	for strategy, expected := range expectations {
+		dropped = 0.0
+		settings.DepositStrategy = strategy
+		depositPheromone(population, performances, []bool{true, true, true}, 0, globalBest, 0.5, &settings)
+		if dropped != expected {
+			t.Errorf("expected strategy %v to drop %v, got %v", strategy, expected, dropped)
+		}
+	}
+}
+
+func TestACODepositStrategies(t *testing.T) {
+	settings := ACOSettings{}
+	settings.Evaporation = 0.9
+	settings.MaxIterations = 5
+	settings.DepositStrategy = RankBasedDeposit
+	if settings.Verify() == nil {
+		t.Error("verification should fail for rank based deposit without rank size")
+	}
+	settings.RankSize = 3
+	settings.DepositStrategy = ElitistDeposit
+	if settings.Verify() == nil {
+		t.Error("verification should fail for elitist deposit without weight")
+	}
+	settings.ElitistWeight = 2.0
+	_, err := ACO([]Ant{ant{true}}, settings)
+	if err == nil {
+		t.Error("elitist deposit should fail for ants not implementing ClonableAnt")
+	}
+	for _, strategy := range []DepositStrategy{IterationBestDeposit, AllAntsDeposit, GlobalBestDeposit, RankBasedDeposit, ElitistDeposit} {
+		settings.DepositStrategy = strategy
+		dropped := 0.0
+		population := []Ant{
+			&recordingAnt{performance: 1.0, dropped: &dropped},
+			&recordingAnt{performance: 2.0, dropped: &dropped},
+		}
+		_, err := ACO(population, settings)
+		if err != nil {
+			t.Errorf("ACO with deposit strategy %v should not fail, got: %v", strategy, err)
+		}
+		if dropped == 0.0 {
+			t.Errorf("expected pheromone to be dropped with strategy %v", strategy)
+		}
+	}
+}
+
+func TestACOSnapshots(t *testing.T) {
+	n := 8
+	distance := circle(n)
+	pheromones := NewDensePheromones(n, 1.0)
+	population := make([]Ant, 5)
+	for i := range population {
+		population[i] = NewGraphAnt(pheromones, distance)
+	}
+	settings := ACOSettings{}
+	settings.Evaporation = 0.9
+	settings.MaxIterations = 20
+	settings.KeepHistory = true
+	res, err := ACO(population, settings)
+	if err != nil {
+		t.Errorf("ACO should not fail, got: %v", err)
+	}
+	for i, best := range res.BestAnts {
+		if best.Performance() != res.BestPerformances[i] {
+			t.Errorf("best ant of iteration %v should keep its tour with performance %v, got %v", i, res.BestPerformances[i], best.Performance())
+		}
+	}
+	best := res.BestAnt.(*GraphAnt)
+	if best.Performance() != res.BestPerformance {
+		t.Errorf("best ant should keep the best tour with performance %v, got %v", res.BestPerformance, best.Performance())
+	}
+	length := 0.0
+	tour := best.Tour()
+	for i := 0; i < len(tour)-1; i++ {
+		length += distance(tour[i], tour[i+1])
+	}
+	if math.Abs(length-res.BestPerformance) > 1e-9 {
+		t.Errorf("tour of best ant should have length %v, got %v", res.BestPerformance, length)
+	}
+}
+
+func TestACOWorkers(t *testing.T) {
+	settings := ACOSettings{}
+	settings.Evaporation = 0.9
+	settings.Workers = -1
+	if settings.Verify() == nil {
+		t.Error("verification should fail for negative number of workers")
+	}
+	settings.Workers = 4
+	settings.Variant = AntColonySystem
+	settings.Exploitation = 0.9
+	settings.LocalEvaporation = 0.1
+	settings.InitialPheromone = 0.01
+	if settings.Verify() == nil {
+		t.Error("verification should fail for concurrent construction with ACS")
+	}
+	settings.Variant = AntSystem
+	settings.MaxIterations = 50
+	n := 8
+	distance := circle(n)
+	pheromones := NewDensePheromones(n, 1.0)
+	population := make([]Ant, 10)
+	for i := range population {
+		population[i] = NewGraphAnt(pheromones, distance)
+	}
+	res, err := ACO(population, settings)
+	if err != nil {
+		t.Errorf("concurrent ACO should not fail, got: %v", err)
+	}
+	if res.FuncEvaluations != len(population)*settings.MaxIterations {
+		t.Errorf("expected %v evaluations, got %v", len(population)*settings.MaxIterations, res.FuncEvaluations)
+	}
+	optimum := float64(n) * distance(0, 1)
+	if res.BestPerformance > optimum+1e-9 {
+		t.Errorf("expected optimal tour length %v, got %v", optimum, res.BestPerformance)
+	}
+}
+
+// stuckAnt reaches a dead end in the first step unless options are given
+type stuckAnt struct {
+	options   []float64
+	deadEnds  int
+	initCalls int
+}
+
+func (a *stuckAnt) Performance() float64 {
+	if a.deadEnds > 0 {
+		return 100.0
+	}
+	return 1.0
+}
+func (a *stuckAnt) DropPheromone(performance float64) {}
+func (a *stuckAnt) PerceivePheromone() []float64      { return a.options }
+func (a *stuckAnt) Evaporate(factor, min float64)     {}
+func (a *stuckAnt) Step(next int) bool                { return true }
+func (a *stuckAnt) Init()                             { a.initCalls++ }
+func (a *stuckAnt) DeadEnd()                          { a.deadEnds++ }
+
+func TestACODeadEnds(t *testing.T) {
+	settings := ACOSettings{}
+	settings.Evaporation = 0.9
+	settings.MaxIterations = 3
+	settings.DeadEndPolicy = DeadEndPolicy(10)
+	if settings.Verify() == nil {
+		t.Error("verification should fail for unknown dead end policy")
+	}
+	settings.DeadEndPolicy = RestartTour
+	settings.MaxRestarts = -1
+	if settings.Verify() == nil {
+		t.Error("verification should fail for negative max restarts")
+	}
+	settings.MaxRestarts = 2
+
+	stuck := &stuckAnt{options: []float64{0.0, 0.0}}
+	res, err := ACO([]Ant{stuck, &stuckAnt{options: []float64{1.0}}}, settings)
+	if err != nil {
+		t.Errorf("ACO should not fail while one ant completes its tour, got: %v", err)
+	}
+	if stuck.initCalls != settings.MaxIterations*(settings.MaxRestarts+1) {
+		t.Errorf("expected stuck ant to be restarted %v times per iteration, got %v inits", settings.MaxRestarts, stuck.initCalls)
+	}
+	if res.FuncEvaluations != settings.MaxIterations {
+		t.Errorf("aborted tours should not be evaluated, got %v evaluations", res.FuncEvaluations)
+	}
+	if res.BestPerformance != 1.0 {
+		t.Errorf("expected best performance of the completing ant, got %v", res.BestPerformance)
+	}
+
+	settings.DeadEndPolicy = AbortTour
+	_, err = ACO([]Ant{&stuckAnt{options: []float64{0.0}}}, settings)
+	if err == nil {
+		t.Error("ACO should fail when no ant completes a tour")
+	}
+
+	settings.DeadEndPolicy = ReportDeadEnd
+	_, err = ACO([]Ant{ant{true}}, settings)
+	if err == nil {
+		t.Error("ReportDeadEnd should fail for ants not implementing DeadEndAnt")
+	}
+	stuck = &stuckAnt{options: []float64{0.0}}
+	res, err = ACO([]Ant{stuck, &stuckAnt{options: []float64{1.0}}}, settings)
+	if err != nil {
+		t.Errorf("ACO should not fail while one ant completes its tour, got: %v", err)
+	}
+	if stuck.deadEnds != settings.MaxIterations {
+		t.Errorf("expected ant to be notified about %v dead ends, got %v", settings.MaxIterations, stuck.deadEnds)
+	}
+	if res.FuncEvaluations != 2*settings.MaxIterations {
+		t.Errorf("reported dead ends should be evaluated, got %v evaluations", res.FuncEvaluations)
+	}
+}
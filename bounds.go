@@ -0,0 +1,146 @@
+package hego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// BoundaryHandling encodes how continuous solvers treat coordinates that leave
+// the feasible box
+type BoundaryHandling int
+
+const (
+	// ClampBoundary sets violating coordinates to the nearest bound
+	ClampBoundary BoundaryHandling = iota
+	// ReflectBoundary mirrors violating coordinates at the bound
+	ReflectBoundary
+	// WrapBoundary treats each dimension as periodic, leaving at the upper bound
+	// means entering at the lower bound
+	WrapBoundary
+	// ReinitBoundary draws violating coordinates uniformly from the feasible range
+	ReinitBoundary
+	// PenaltyBoundary leaves positions unchanged but adds Penalty times the
+	// squared distance to the feasible box to the objective value
+	PenaltyBoundary
+)
+
+// Bounds represents box constraints of continuous solvers. When Lower and Upper
+// are empty, the search space is unbounded
+type Bounds struct {
+	// Lower is the lower bound of every dimension
+	Lower []float64
+	// Upper is the upper bound of every dimension
+	Upper []float64
+	// Handling determines how positions outside of the bounds are treated
+	Handling BoundaryHandling
+	// Penalty is the weight of the squared constraint violation for PenaltyBoundary
+	Penalty float64
+}
+
+// Verify checks the validity of the bounds and returns nil if everything is ok
+func (b *Bounds) Verify() error {
+	if len(b.Lower) != len(b.Upper) {
+		return fmt.Errorf("lower and upper bounds must have the same length, got %v and %v", len(b.Lower), len(b.Upper))
+	}
+	for i := range b.Lower {
+		if b.Lower[i] > b.Upper[i] {
+			return fmt.Errorf("lower bound %v is greater than upper bound %v in dimension %v", b.Lower[i], b.Upper[i], i)
+		}
+	}
+	if b.Handling < ClampBoundary || b.Handling > PenaltyBoundary {
+		return fmt.Errorf("unknown boundary handling %v", b.Handling)
+	}
+	if b.Handling == PenaltyBoundary && b.Penalty <= 0.0 {
+		return fmt.Errorf("penalty must be greater than 0, got %v", b.Penalty)
+	}
+	return nil
+}
+
+// bounded returns true if box constraints are set
+func (b *Bounds) bounded() bool {
+	return len(b.Lower) > 0
+}
+
+// verifyDimension returns an error if the bounds do not match the dimension n
+func (b *Bounds) verifyDimension(n int) error {
+	if b.bounded() && len(b.Lower) != n {
+		return fmt.Errorf("bounds have dimension %v, but the problem has dimension %v", len(b.Lower), n)
+	}
+	return nil
+}
+
+// repair moves x back into the bounds in place. When velocity is not nil, the
+// velocity of repaired coordinates is adjusted as well: reflected coordinates
+// reverse their velocity, clamped and reinitialized coordinates stop. With
+// PenaltyBoundary x is not changed
+func (b *Bounds) repair(x, velocity []float64) {
+	if !b.bounded() || b.Handling == PenaltyBoundary {
+		return
+	}
+	for i, v := range x {
+		lower, upper := b.Lower[i], b.Upper[i]
+		if v >= lower && v <= upper {
+			continue
+		}
+		width := upper - lower
+		switch b.Handling {
+		case ClampBoundary:
+			x[i] = math.Max(lower, math.Min(upper, v))
+		case ReflectBoundary:
+			// reflecting repeatedly at both bounds is periodic with 2 * width
+			t := positiveMod(v-lower, 2*width)
+			if t > width {
+				t = 2*width - t
+			}
+			x[i] = lower + t
+		case WrapBoundary:
+			x[i] = lower + positiveMod(v-lower, width)
+		case ReinitBoundary:
+			x[i] = lower + rand.Float64()*width
+		}
+		if velocity != nil {
+			if b.Handling == ReflectBoundary {
+				velocity[i] = -velocity[i]
+			} else if b.Handling != WrapBoundary {
+				velocity[i] = 0.0
+			}
+		}
+	}
+}
+
+// violation returns the squared euclidean distance of x to the bounds
+func (b *Bounds) violation(x []float64) float64 {
+	if !b.bounded() {
+		return 0.0
+	}
+	res := 0.0
+	for i, v := range x {
+		if v < b.Lower[i] {
+			res += (b.Lower[i] - v) * (b.Lower[i] - v)
+		} else if v > b.Upper[i] {
+			res += (v - b.Upper[i]) * (v - b.Upper[i])
+		}
+	}
+	return res
+}
+
+// penalty returns the penalty for x, which is 0 unless PenaltyBoundary is used
+func (b *Bounds) penalty(x []float64) float64 {
+	if b.Handling != PenaltyBoundary {
+		return 0.0
+	}
+	return b.Penalty * b.violation(x)
+}
+
+// positiveMod returns x modulo m in [0, m). Returns 0 for m = 0
+func positiveMod(x, m float64) float64 {
+	if m == 0.0 {
+		return 0.0
+	}
+	res := math.Mod(x, m)
+	if res < 0.0 {
+		res += m
+	}
+	return res
+}
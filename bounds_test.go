@@ -0,0 +1,87 @@
+package hego
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVerifyBounds(t *testing.T) {
+	b := Bounds{}
+	if err := b.Verify(); err != nil {
+		t.Errorf("empty bounds should be valid, got: %v", err)
+	}
+	b.Lower = []float64{0.0}
+	if b.Verify() == nil {
+		t.Error("expected verification to fail with different lengths")
+	}
+	b.Upper = []float64{-1.0}
+	if b.Verify() == nil {
+		t.Error("expected verification to fail with lower > upper")
+	}
+	b.Upper = []float64{1.0}
+	b.Handling = BoundaryHandling(10)
+	if b.Verify() == nil {
+		t.Error("expected verification to fail with unknown handling")
+	}
+	b.Handling = PenaltyBoundary
+	if b.Verify() == nil {
+		t.Error("expected verification to fail with penalty handling and penalty 0")
+	}
+	b.Penalty = 10.0
+	if err := b.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+	if b.verifyDimension(2) == nil {
+		t.Error("expected dimension check to fail")
+	}
+}
+
+func TestBoundsRepair(t *testing.T) {
+	cases := []struct {
+		handling BoundaryHandling
+		x        []float64
+		want     []float64
+		velocity []float64
+	}{
+		{ClampBoundary, []float64{-1.0, 0.5, 3.0}, []float64{0.0, 0.5, 2.0}, []float64{0.0, 1.0, 0.0}},
+		{ReflectBoundary, []float64{-0.5, 0.5, 2.5}, []float64{0.5, 0.5, 1.5}, []float64{-1.0, 1.0, -1.0}},
+		{ReflectBoundary, []float64{-3.0, 0.5, 6.5}, []float64{1.0, 0.5, 1.5}, []float64{-1.0, 1.0, -1.0}},
+		{WrapBoundary, []float64{-0.5, 0.5, 2.5}, []float64{1.5, 0.5, 0.5}, []float64{1.0, 1.0, 1.0}},
+		{PenaltyBoundary, []float64{-0.5, 0.5, 2.5}, []float64{-0.5, 0.5, 2.5}, []float64{1.0, 1.0, 1.0}},
+	}
+	for _, c := range cases {
+		b := Bounds{Lower: []float64{0.0, 0.0, 0.0}, Upper: []float64{2.0, 2.0, 2.0}, Handling: c.handling}
+		velocity := []float64{1.0, 1.0, 1.0}
+		b.repair(c.x, velocity)
+		for i := range c.x {
+			if math.Abs(c.x[i]-c.want[i]) > 1e-12 {
+				t.Errorf("handling %v: expected %v, got %v", c.handling, c.want, c.x)
+			}
+			if velocity[i] != c.velocity[i] {
+				t.Errorf("handling %v: expected velocity %v, got %v", c.handling, c.velocity, velocity)
+			}
+		}
+	}
+	b := Bounds{Lower: []float64{0.0}, Upper: []float64{2.0}, Handling: ReinitBoundary}
+	for i := 0; i < 100; i++ {
+		x := []float64{5.0}
+		b.repair(x, nil)
+		if x[0] < 0.0 || x[0] > 2.0 {
+			t.Errorf("reinitialized coordinate should be within bounds, got %v", x[0])
+		}
+	}
+}
+
+func TestBoundsPenalty(t *testing.T) {
+	b := Bounds{Lower: []float64{0.0, 0.0}, Upper: []float64{1.0, 1.0}, Handling: PenaltyBoundary, Penalty: 2.0}
+	if p := b.penalty([]float64{-1.0, 3.0}); p != 10.0 {
+		t.Errorf("expected penalty 2 * (1 + 4) = 10, got %v", p)
+	}
+	if p := b.penalty([]float64{0.5, 0.5}); p != 0.0 {
+		t.Errorf("expected no penalty inside bounds, got %v", p)
+	}
+	b.Handling = ClampBoundary
+	if p := b.penalty([]float64{-1.0, 3.0}); p != 0.0 {
+		t.Errorf("expected no penalty without PenaltyBoundary, got %v", p)
+	}
+}
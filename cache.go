@@ -0,0 +1,77 @@
+package hego
+
+import "container/list"
+
+// Keyed can be implemented by a Genome, AnnealingState or TabuState to enable
+// the evaluation cache. Candidates with equal keys must have equal objective
+// values
+type Keyed interface {
+	// Key returns a unique identifier of the candidate
+	Key() string
+}
+
+type cacheEntry struct {
+	key   string
+	value float64
+}
+
+// evalCache is a least recently used cache of objective values. A nil
+// *evalCache is valid and caches nothing
+type evalCache struct {
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// newEvalCache returns a cache holding up to size values or nil if size is 0
+func newEvalCache(size int) *evalCache {
+	if size <= 0 {
+		return nil
+	}
+	return &evalCache{
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+// lookup returns the cached value for candidate if it implements Keyed
+func (c *evalCache) lookup(candidate interface{}) (float64, bool) {
+	if c == nil {
+		return 0.0, false
+	}
+	k, ok := candidate.(Keyed)
+	if !ok {
+		return 0.0, false
+	}
+	elem, ok := c.entries[k.Key()]
+	if !ok {
+		return 0.0, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).value, true
+}
+
+// store adds the value for candidate if it implements Keyed and evicts the
+// least recently used value when the cache is full
+func (c *evalCache) store(candidate interface{}, value float64) {
+	if c == nil {
+		return
+	}
+	k, ok := candidate.(Keyed)
+	if !ok {
+		return
+	}
+	key := k.Key()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheEntry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, value: value})
+}
@@ -0,0 +1,43 @@
+package hego
+
+import (
+	"fmt"
+	"testing"
+)
+
+type keyedGenome float64
+
+func (k keyedGenome) Key() string { return fmt.Sprint(float64(k)) }
+
+func TestEvalCache(t *testing.T) {
+	var nilCache *evalCache
+	nilCache.store(keyedGenome(1.0), 1.0)
+	if _, ok := nilCache.lookup(keyedGenome(1.0)); ok {
+		t.Error("nil cache should never hit")
+	}
+	if newEvalCache(0) != nil {
+		t.Error("cache with size 0 should be nil")
+	}
+
+	cache := newEvalCache(2)
+	cache.store(genome(1.0), 1.0)
+	if _, ok := cache.lookup(genome(1.0)); ok {
+		t.Error("candidates without Key should not be cached")
+	}
+	cache.store(keyedGenome(1.0), 1.0)
+	cache.store(keyedGenome(2.0), 4.0)
+	if v, ok := cache.lookup(keyedGenome(1.0)); !ok || v != 1.0 {
+		t.Errorf("expected cache hit with value 1.0, got %v, %v", v, ok)
+	}
+	// 2.0 is least recently used now and should be evicted
+	cache.store(keyedGenome(3.0), 9.0)
+	if _, ok := cache.lookup(keyedGenome(2.0)); ok {
+		t.Error("least recently used value should have been evicted")
+	}
+	if _, ok := cache.lookup(keyedGenome(1.0)); !ok {
+		t.Error("recently used value should still be cached")
+	}
+	if _, ok := cache.lookup(keyedGenome(3.0)); !ok {
+		t.Error("latest value should be cached")
+	}
+}
@@ -0,0 +1,397 @@
+package hego
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// CMAESResult represents the result of the covariance matrix adaptation evolution strategy
+type CMAESResult struct {
+	// Candidates holds the mean of the search distribution after every iteration
+	Candidates        [][]float64
+	AverageObjectives []float64
+	BestObjectives    []float64
+	// Sigmas holds the step size after every iteration
+	Sigmas        []float64
+	BestCandidate []float64
+	BestObjective float64
+	// Restarts is the number of restarts performed
+	Restarts int
+	Result
+}
+
+// CMAESSettings represents settings for the covariance matrix adaptation evolution strategy
+type CMAESSettings struct {
+	// PopulationSize is the number of candidates sampled in every iteration. When 0,
+	// the default 4 + 3 ln(n) is used, where n is the dimension of the problem
+	PopulationSize int
+	// Sigma is the initial step size. It should be about a third of the
+	// expected distance between x0 and the optimum
+	Sigma float64
+	// Restarts is the maximum number of restarts when the search stagnates
+	Restarts int
+	// PopulationIncrease is the factor the population grows by on every restart
+	// (IPOP). When 0, the population is doubled
+	PopulationIncrease float64
+	// Tolerance triggers a restart when the objective values of recent iterations
+	// or the step size fall below it. When 0, 1e-12 is used
+	Tolerance float64
+	Bounds
+	Settings
+}
+
+// Verify checks the validity of the settings and returns nil if everything is ok
+func (s *CMAESSettings) Verify() error {
+	if s.Sigma <= 0.0 {
+		return fmt.Errorf("sigma must be greater than 0, got %v", s.Sigma)
+	}
+	if s.PopulationSize != 0 && s.PopulationSize < 2 {
+		return fmt.Errorf("population size must be at least 2, got %v", s.PopulationSize)
+	}
+	if s.MaxIterations <= 0 {
+		return fmt.Errorf("max iterations must be greater than 0, got %v", s.MaxIterations)
+	}
+	if s.Restarts < 0 {
+		return fmt.Errorf("number of restarts must not be negative, got %v", s.Restarts)
+	}
+	if s.PopulationIncrease != 0.0 && s.PopulationIncrease < 1.0 {
+		return fmt.Errorf("population increase must be at least 1, got %v", s.PopulationIncrease)
+	}
+	if s.Tolerance < 0.0 {
+		return errors.New("tolerance must not be negative")
+	}
+	return s.Bounds.Verify()
+}
+
+// cmaParams holds the strategy parameters derived from dimension and population size
+type cmaParams struct {
+	weights                             []float64
+	mueff, cc, cs, c1, cmu, damps, chiN float64
+}
+
+// newCMAParams returns the default strategy parameters from Hansen's tutorial
+func newCMAParams(n, lambda int) cmaParams {
+	p := cmaParams{weights: make([]float64, lambda/2)}
+	nf := float64(n)
+	sum, sumSq := 0.0, 0.0
+	for i := range p.weights {
+		p.weights[i] = math.Log(float64(len(p.weights))+0.5) - math.Log(float64(i+1))
+		sum += p.weights[i]
+	}
+	for i := range p.weights {
+		p.weights[i] /= sum
+		sumSq += p.weights[i] * p.weights[i]
+	}
+	p.mueff = 1.0 / sumSq
+	p.cc = (4.0 + p.mueff/nf) / (nf + 4.0 + 2.0*p.mueff/nf)
+	p.cs = (p.mueff + 2.0) / (nf + p.mueff + 5.0)
+	p.c1 = 2.0 / ((nf+1.3)*(nf+1.3) + p.mueff)
+	p.cmu = math.Min(1.0-p.c1, 2.0*(p.mueff-2.0+1.0/p.mueff)/((nf+2.0)*(nf+2.0)+p.mueff))
+	p.damps = 1.0 + 2.0*math.Max(0.0, math.Sqrt((p.mueff-1.0)/(nf+1.0))-1.0) + p.cs
+	p.chiN = math.Sqrt(nf) * (1.0 - 1.0/(4.0*nf) + 1.0/(21.0*nf*nf))
+	return p
+}
+
+// symmetricEigen computes the eigendecomposition of the symmetric matrix a
+// with the cyclic Jacobi method. It returns the eigenvalues and the
+// eigenvectors as columns of the second return value. a is not modified
+func symmetricEigen(a [][]float64) ([]float64, [][]float64) {
+	n := len(a)
+	m := make([][]float64, n)
+	v := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		copy(m[i], a[i])
+		v[i] = make([]float64, n)
+		v[i][i] = 1.0
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += m[i][j] * m[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if m[p][q] == 0.0 {
+					continue
+				}
+				// rotation angle that eliminates m[p][q]
+				theta := (m[q][q] - m[p][p]) / (2.0 * m[p][q])
+				t := 1.0 / (math.Abs(theta) + math.Sqrt(theta*theta+1.0))
+				if theta < 0.0 {
+					t = -t
+				}
+				c := 1.0 / math.Sqrt(t*t+1.0)
+				s := t * c
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p] = c*mkp - s*mkq
+					m[k][q] = s*mkp + c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k] = c*mpk - s*mqk
+					m[q][k] = s*mpk + c*mqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = m[i][i]
+	}
+	return values, v
+}
+
+// CMAES performs the Covariance Matrix Adaptation Evolution Strategy for
+// minimizing a real valued function (objective) from a starting point x0.
+// It adapts a full covariance matrix of the search distribution with rank-one
+// and rank-mu updates and controls the step size with cumulative step length
+// adaptation. This makes it suited for ill-conditioned and non separable
+// problems. When the search stagnates, it restarts with an increased
+// population (IPOP). MaxIterations limits the iterations of all runs combined
+func CMAES(
+	objective func(x []float64) float64,
+	x0 []float64,
+	settings CMAESSettings) (res CMAESResult, err error) {
+	err = settings.Verify()
+	if err == nil {
+		err = settings.verifyDimension(len(x0))
+	}
+	if err == nil && len(x0) == 0 {
+		err = errors.New("x0 must not be empty")
+	}
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return res, err
+	}
+	start := time.Now()
+	logger := newLogger("Covariance Matrix Adaptation Evolution Strategy", []string{"Iteration", "Population Mean", "Population Best", "Sigma"}, settings.Verbose, settings.MaxIterations)
+	// increase funcEvaluations counter for every call to objective
+	evaluate := func(x []float64) float64 {
+		res.FuncEvaluations++
+		return objective(x) + settings.penalty(x)
+	}
+	n := len(x0)
+	lambda := settings.PopulationSize
+	if lambda == 0 {
+		lambda = 4 + int(3.0*math.Log(float64(n)))
+	}
+	increase := settings.PopulationIncrease
+	if increase == 0.0 {
+		increase = 2.0
+	}
+	tolerance := settings.Tolerance
+	if tolerance == 0.0 {
+		tolerance = 1e-12
+	}
+
+	if settings.KeepHistory {
+		res.Candidates = make([][]float64, 0, settings.MaxIterations)
+		res.AverageObjectives = make([]float64, 0, settings.MaxIterations)
+		res.BestObjectives = make([]float64, 0, settings.MaxIterations)
+		res.Sigmas = make([]float64, 0, settings.MaxIterations)
+	}
+	res.BestObjective = math.MaxFloat64
+	res.BestCandidate = make([]float64, n)
+
+	iteration := 0
+	for run := 0; run <= settings.Restarts && iteration < settings.MaxIterations; run++ {
+		if run > 0 {
+			res.Restarts++
+			lambda = int(math.Ceil(float64(lambda) * increase))
+		}
+		p := newCMAParams(n, lambda)
+
+		// initialize the search distribution
+		mean := make([]float64, n)
+		copy(mean, x0)
+		if run > 0 && settings.bounded() {
+			for i := range mean {
+				mean[i] = settings.Lower[i] + rand.Float64()*(settings.Upper[i]-settings.Lower[i])
+			}
+		}
+		settings.repair(mean, nil)
+		sigma := settings.Sigma
+		pc, ps := make([]float64, n), make([]float64, n)
+		// C = B diag(D^2) B^T, invsqrtC = B diag(1/D) B^T
+		cov, b, invsqrtC := make([][]float64, n), make([][]float64, n), make([][]float64, n)
+		d := make([]float64, n)
+		for i := 0; i < n; i++ {
+			cov[i], b[i], invsqrtC[i] = make([]float64, n), make([]float64, n), make([]float64, n)
+			cov[i][i], b[i][i], invsqrtC[i][i] = 1.0, 1.0, 1.0
+			d[i] = 1.0
+		}
+		eigenIteration := 0
+
+		xs, ys, z := make([][]float64, lambda), make([][]float64, lambda), make([]float64, n)
+		for k := range xs {
+			xs[k], ys[k] = make([]float64, n), make([]float64, n)
+		}
+		objs, order := make([]float64, lambda), make([]int, lambda)
+		// history of best objectives to detect stagnation
+		window := 10 + int(math.Ceil(30.0*float64(n)/float64(lambda)))
+		recent := make([]float64, 0, window)
+
+		for gen := 0; iteration < settings.MaxIterations; gen++ {
+			// sample population x = mean + sigma * B D z
+			totalObj := 0.0
+			for k := range xs {
+				for i := range z {
+					z[i] = rand.NormFloat64() * d[i]
+				}
+				for i := 0; i < n; i++ {
+					ys[k][i] = 0.0
+					for j := 0; j < n; j++ {
+						ys[k][i] += b[i][j] * z[j]
+					}
+					xs[k][i] = mean[i] + sigma*ys[k][i]
+				}
+				if settings.bounded() {
+					settings.repair(xs[k], nil)
+					for i := range ys[k] {
+						ys[k][i] = (xs[k][i] - mean[i]) / sigma
+					}
+				}
+				objs[k] = evaluate(xs[k])
+				totalObj += objs[k]
+				order[k] = k
+			}
+			sort.Slice(order, func(i, j int) bool { return objs[order[i]] < objs[order[j]] })
+			best := order[0]
+			if objs[best] < res.BestObjective {
+				res.BestObjective = objs[best]
+				copy(res.BestCandidate, xs[best])
+			}
+
+			// recombination of the mu best candidates
+			yw := make([]float64, n)
+			for r, w := range p.weights {
+				for i := range yw {
+					yw[i] += w * ys[order[r]][i]
+				}
+			}
+			for i := range mean {
+				mean[i] += sigma * yw[i]
+			}
+
+			// cumulation of the evolution paths
+			normPs := 0.0
+			for i := range ps {
+				cy := 0.0
+				for j := range yw {
+					cy += invsqrtC[i][j] * yw[j]
+				}
+				ps[i] = (1.0-p.cs)*ps[i] + math.Sqrt(p.cs*(2.0-p.cs)*p.mueff)*cy
+				normPs += ps[i] * ps[i]
+			}
+			normPs = math.Sqrt(normPs)
+			hsig := 0.0
+			if normPs/math.Sqrt(1.0-math.Pow(1.0-p.cs, 2.0*float64(gen+1)))/p.chiN < 1.4+2.0/float64(n+1) {
+				hsig = 1.0
+			}
+			for i := range pc {
+				pc[i] = (1.0-p.cc)*pc[i] + hsig*math.Sqrt(p.cc*(2.0-p.cc)*p.mueff)*yw[i]
+			}
+
+			// rank-one and rank-mu update of the covariance matrix
+			for i := 0; i < n; i++ {
+				for j := 0; j <= i; j++ {
+					rankMu := 0.0
+					for r, w := range p.weights {
+						rankMu += w * ys[order[r]][i] * ys[order[r]][j]
+					}
+					rankOne := pc[i]*pc[j] + (1.0-hsig)*p.cc*(2.0-p.cc)*cov[i][j]
+					cov[i][j] = (1.0-p.c1-p.cmu)*cov[i][j] + p.c1*rankOne + p.cmu*rankMu
+					cov[j][i] = cov[i][j]
+				}
+			}
+
+			// cumulative step length adaptation
+			sigma *= math.Exp(p.cs / p.damps * (normPs/p.chiN - 1.0))
+
+			// the eigendecomposition is only updated every few iterations, as its
+			// cost would dominate for larger dimensions
+			if float64(gen-eigenIteration) > float64(lambda)/(p.c1+p.cmu)/float64(n)/10.0 {
+				eigenIteration = gen
+				var values []float64
+				values, b = symmetricEigen(cov)
+				for i := range values {
+					d[i] = math.Sqrt(math.Max(values[i], 1e-20))
+				}
+				for i := 0; i < n; i++ {
+					for j := 0; j < n; j++ {
+						invsqrtC[i][j] = 0.0
+						for k := 0; k < n; k++ {
+							invsqrtC[i][j] += b[i][k] / d[k] * b[j][k]
+						}
+					}
+				}
+			}
+
+			if settings.KeepHistory {
+				candidate := make([]float64, n)
+				copy(candidate, mean)
+				res.Candidates = append(res.Candidates, candidate)
+				res.AverageObjectives = append(res.AverageObjectives, totalObj/float64(lambda))
+				res.BestObjectives = append(res.BestObjectives, objs[best])
+				res.Sigmas = append(res.Sigmas, sigma)
+			}
+			logger.AddLine(iteration, []string{
+				fmt.Sprint(iteration),
+				fmt.Sprint(totalObj / float64(lambda)),
+				fmt.Sprint(objs[best]),
+				fmt.Sprint(sigma),
+			})
+			iteration++
+
+			// stop this run when the objective values or the step size stagnate
+			// or the covariance matrix becomes ill-conditioned
+			if len(recent) == window {
+				recent = recent[1:]
+			}
+			recent = append(recent, objs[best])
+			maxD, minD := d[0], d[0]
+			for _, v := range d {
+				maxD, minD = math.Max(maxD, v), math.Min(minD, v)
+			}
+			if len(recent) == window && floatRange(recent) < tolerance ||
+				objs[order[lambda-1]]-objs[best] < tolerance && gen > 0 ||
+				sigma*maxD < tolerance ||
+				maxD/minD > 1e7 {
+				break
+			}
+		}
+	}
+
+	res.Runtime = time.Since(start)
+	res.Iterations = iteration
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return res, nil
+}
+
+// floatRange returns the difference between the largest and the smallest value of x
+func floatRange(x []float64) float64 {
+	min, max := x[0], x[0]
+	for _, v := range x {
+		min, max = math.Min(min, v), math.Max(max, v)
+	}
+	return max - min
+}
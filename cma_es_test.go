@@ -0,0 +1,120 @@
+package hego
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVerifyCMAESSettings(t *testing.T) {
+	settings := CMAESSettings{}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail without sigma")
+	}
+	settings.Sigma = 1.0
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail without max iterations")
+	}
+	settings.MaxIterations = 10
+	settings.PopulationSize = 1
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with population size 1")
+	}
+	settings.PopulationSize = 0
+	settings.PopulationIncrease = 0.5
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with shrinking population")
+	}
+	settings.PopulationIncrease = 0.0
+	settings.Restarts = -1
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with negative restarts")
+	}
+	settings.Restarts = 2
+	if err := settings.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+}
+
+func TestSymmetricEigen(t *testing.T) {
+	a := [][]float64{{4.0, 1.0, 0.0}, {1.0, 3.0, 1.0}, {0.0, 1.0, 2.0}}
+	values, vectors := symmetricEigen(a)
+	// check A v = lambda v for every eigenpair
+	for k, lambda := range values {
+		for i := range a {
+			av := 0.0
+			for j := range a {
+				av += a[i][j] * vectors[j][k]
+			}
+			if math.Abs(av-lambda*vectors[i][k]) > 1e-9 {
+				t.Errorf("eigenpair %v does not satisfy A v = lambda v", k)
+			}
+		}
+	}
+	trace := values[0] + values[1] + values[2]
+	if math.Abs(trace-9.0) > 1e-9 {
+		t.Errorf("expected eigenvalues to sum up to the trace 9, got %v", trace)
+	}
+}
+
+func TestCMAES(t *testing.T) {
+	// rotated, ill-conditioned ellipsoid
+	ellipsoid := func(x []float64) float64 {
+		res := 0.0
+		for i := range x {
+			s := 0.0
+			for j := 0; j <= i; j++ {
+				s += x[j]
+			}
+			res += math.Pow(1e3, float64(i)/float64(len(x)-1)) * s * s
+		}
+		return res
+	}
+	x0 := []float64{3.0, -2.0, 1.0, 4.0, -1.0}
+	settings := CMAESSettings{}
+	_, err := CMAES(ellipsoid, x0, settings)
+	if err == nil {
+		t.Error("CMAES should fail with invalid settings")
+	}
+	settings.Sigma = 1.0
+	settings.MaxIterations = 1000
+	settings.KeepHistory = true
+	res, err := CMAES(ellipsoid, x0, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in CMAES: %v", err)
+	}
+	if res.BestObjective > 1e-8 {
+		t.Errorf("CMAES did not find the optimum of the ellipsoid, got %v", res.BestObjective)
+	}
+	if len(res.Sigmas) != res.Iterations || len(res.Candidates) != res.Iterations {
+		t.Errorf("expected history for all %v iterations, got %v", res.Iterations, len(res.Sigmas))
+	}
+	if res.Iterations >= settings.MaxIterations {
+		t.Error("expected CMAES to stop early after convergence")
+	}
+}
+
+func TestCMAESRestarts(t *testing.T) {
+	rastrigin := func(x []float64) float64 {
+		res := 10.0 * float64(len(x))
+		for _, v := range x {
+			res += v*v - 10.0*math.Cos(2.0*math.Pi*v)
+		}
+		return res
+	}
+	settings := CMAESSettings{}
+	settings.Sigma = 2.0
+	settings.MaxIterations = 2000
+	settings.Restarts = 5
+	settings.Lower = []float64{-5.12, -5.12}
+	settings.Upper = []float64{5.12, 5.12}
+	res, err := CMAES(rastrigin, []float64{4.0, 4.0}, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in CMAES: %v", err)
+	}
+	if res.Restarts == 0 {
+		t.Error("expected CMAES to restart on rastrigin")
+	}
+	if res.BestObjective > 1e-6 {
+		t.Errorf("expected CMAES with restarts to find the global optimum, got %v", res.BestObjective)
+	}
+}
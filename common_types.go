@@ -24,5 +24,8 @@ type Settings struct {
 type Result struct {
 	Runtime         time.Duration
 	FuncEvaluations int
-	Iterations      int
+	// CacheHits is the number of objective evaluations answered by the
+	// evaluation cache. These are not included in FuncEvaluations
+	CacheHits  int
+	Iterations int
 }
@@ -0,0 +1,175 @@
+package hego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// CuckooResult represents the result of the cuckoo search algorithm
+type CuckooResult struct {
+	BestCandidates [][]float64
+	BestObjectives []float64
+	BestCandidate  []float64
+	BestObjective  float64
+	Result
+}
+
+// CuckooSettings represents settings for the cuckoo search algorithm
+type CuckooSettings struct {
+	// Nests is the number of host nests, at least 3
+	Nests int
+	// DiscoveryRate in [0, 1] is the probability of every coordinate of a nest
+	// to be rebuilt after discovery of the cuckoo egg
+	DiscoveryRate float64
+	// StepSize scales the Lévy flights relative to the distance to the best nest
+	StepSize float64
+	// Beta in (0, 2) is the exponent of the Lévy distribution. Smaller values
+	// produce more long jumps. When 0, 1.5 is used
+	Beta float64
+	Bounds
+	Settings
+}
+
+// Verify checks the validity of the settings and returns nil if everything is ok
+func (s *CuckooSettings) Verify() error {
+	if s.Nests < 3 {
+		return fmt.Errorf("number of nests must be at least 3, got %v", s.Nests)
+	}
+	if s.DiscoveryRate < 0.0 || s.DiscoveryRate > 1.0 {
+		return fmt.Errorf("discovery rate must be in [0, 1], got %v", s.DiscoveryRate)
+	}
+	if s.StepSize <= 0.0 {
+		return fmt.Errorf("step size must be greater than 0, got %v", s.StepSize)
+	}
+	if s.Beta < 0.0 || s.Beta >= 2.0 {
+		return fmt.Errorf("beta must be in (0, 2), got %v", s.Beta)
+	}
+	return s.Bounds.Verify()
+}
+
+// levyScale returns the standard deviation of the numerator in Mantegna's
+// algorithm for the Lévy exponent beta
+func levyScale(beta float64) float64 {
+	num := math.Gamma(1.0+beta) * math.Sin(math.Pi*beta/2.0)
+	den := math.Gamma((1.0+beta)/2.0) * beta * math.Pow(2.0, (beta-1.0)/2.0)
+	return math.Pow(num/den, 1.0/beta)
+}
+
+// levyStep draws a step from a symmetric Lévy stable distribution with
+// Mantegna's algorithm, scale is the result of levyScale
+func levyStep(beta, scale float64) float64 {
+	u := rand.NormFloat64() * scale
+	v := rand.NormFloat64()
+	return u / math.Pow(math.Abs(v), 1.0/beta)
+}
+
+// Cuckoo performs Cuckoo Search (Yang and Deb) for minimizing a real valued
+// function (objective). init is called to create every nest of the initial
+// population. Every iteration lays a cuckoo egg next to each nest with a Lévy
+// flight scaled by the distance to the best nest. Afterwards a fraction of
+// the nests is discovered and rebuilt by random differences of other nests.
+// New nests only replace old ones when they are better
+func Cuckoo(
+	objective func(x []float64) float64,
+	init func() []float64,
+	settings CuckooSettings) (res CuckooResult, err error) {
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return res, err
+	}
+	start := time.Now()
+	logger := newLogger("Cuckoo Search", []string{"Iteration", "Population Mean", "Population Best"}, settings.Verbose, settings.MaxIterations)
+	// increase funcEvaluations counter for every call to objective
+	evaluate := func(x []float64) float64 {
+		res.FuncEvaluations++
+		return objective(x) + settings.penalty(x)
+	}
+	if settings.KeepHistory {
+		res.BestCandidates = make([][]float64, 0, settings.MaxIterations)
+		res.BestObjectives = make([]float64, 0, settings.MaxIterations)
+	}
+	beta := settings.Beta
+	if beta == 0.0 {
+		beta = 1.5
+	}
+	scale := levyScale(beta)
+
+	nests := make([][]float64, settings.Nests)
+	objs := make([]float64, settings.Nests)
+	best := 0
+	for i := range nests {
+		nests[i] = init()
+		if err = settings.verifyDimension(len(nests[i])); err != nil {
+			return res, err
+		}
+		settings.repair(nests[i], nil)
+		objs[i] = evaluate(nests[i])
+		if objs[i] < objs[best] {
+			best = i
+		}
+	}
+
+	n := len(nests[0])
+	// replace keeps the new nest when it is better than the j-th nest
+	replace := func(j int, nest []float64) {
+		settings.repair(nest, nil)
+		if obj := evaluate(nest); obj < objs[j] {
+			nests[j], objs[j] = nest, obj
+		}
+	}
+	for i := 0; i < settings.MaxIterations; i++ {
+		// lay eggs by lévy flights, the best nest stays where it is
+		for j, x := range nests {
+			egg := make([]float64, n)
+			for d := range egg {
+				egg[d] = x[d] + settings.StepSize*levyStep(beta, scale)*(x[d]-nests[best][d])*rand.NormFloat64()
+			}
+			replace(j, egg)
+		}
+		// discover eggs and rebuild the nests
+		for j, x := range nests {
+			r := distinctIndizes(settings.Nests, 2, j)
+			nest := make([]float64, n)
+			for d := range nest {
+				nest[d] = x[d]
+				if rand.Float64() < settings.DiscoveryRate {
+					nest[d] += rand.Float64() * (nests[r[0]][d] - nests[r[1]][d])
+				}
+			}
+			replace(j, nest)
+		}
+
+		totalObj := 0.0
+		for j := range objs {
+			totalObj += objs[j]
+			if objs[j] < objs[best] {
+				best = j
+			}
+		}
+		if settings.KeepHistory {
+			candidate := make([]float64, n)
+			copy(candidate, nests[best])
+			res.BestCandidates = append(res.BestCandidates, candidate)
+			res.BestObjectives = append(res.BestObjectives, objs[best])
+		}
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(totalObj / float64(settings.Nests)),
+			fmt.Sprint(objs[best]),
+		})
+	}
+	res.BestObjective = objs[best]
+	res.BestCandidate = make([]float64, n)
+	copy(res.BestCandidate, nests[best])
+
+	res.Runtime = time.Since(start)
+	res.Iterations = settings.MaxIterations
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return res, nil
+}
@@ -0,0 +1,89 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVerifyCuckooSettings(t *testing.T) {
+	settings := CuckooSettings{Nests: 2}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with 2 nests")
+	}
+	settings.Nests = 15
+	settings.DiscoveryRate = 1.5
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with discovery rate > 1")
+	}
+	settings.DiscoveryRate = 0.25
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with step size 0")
+	}
+	settings.StepSize = 0.01
+	settings.Beta = 2.0
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with beta = 2")
+	}
+	settings.Beta = 0.0
+	if err := settings.Verify(); err != nil {
+		t.Errorf("expected verification to pass with default beta, got: %v", err)
+	}
+}
+
+func TestLevyStep(t *testing.T) {
+	// for beta = 1 the lévy distribution is cauchy, whose median absolute value is 1
+	beta := 1.0
+	scale := levyScale(beta)
+	if math.Abs(scale-1.0) > 1e-12 {
+		t.Errorf("expected scale 1 for beta = 1, got %v", scale)
+	}
+	n, below := 100000, 0
+	for i := 0; i < n; i++ {
+		if math.Abs(levyStep(beta, scale)) < 1.0 {
+			below++
+		}
+	}
+	if math.Abs(float64(below)/float64(n)-0.5) > 0.01 {
+		t.Errorf("expected half of the cauchy steps below 1, got %v", float64(below)/float64(n))
+	}
+}
+
+func TestCuckoo(t *testing.T) {
+	rastrigin := func(x []float64) float64 {
+		res := 10.0 * float64(len(x))
+		for _, v := range x {
+			res += v*v - 10.0*math.Cos(2.0*math.Pi*v)
+		}
+		return res
+	}
+	init := func() []float64 {
+		x := make([]float64, 2)
+		for i := range x {
+			x[i] = -5.12 + 10.24*rand.Float64()
+		}
+		return x
+	}
+	settings := CuckooSettings{}
+	_, err := Cuckoo(rastrigin, init, settings)
+	if err == nil {
+		t.Error("Cuckoo should fail with invalid settings")
+	}
+	settings.Nests = 25
+	settings.DiscoveryRate = 0.25
+	settings.StepSize = 0.01
+	settings.MaxIterations = 500
+	settings.KeepHistory = true
+	settings.Lower = []float64{-5.12, -5.12}
+	settings.Upper = []float64{5.12, 5.12}
+	res, err := Cuckoo(rastrigin, init, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in Cuckoo: %v", err)
+	}
+	if len(res.BestObjectives) != settings.MaxIterations {
+		t.Errorf("expected history for every iteration, got %v", len(res.BestObjectives))
+	}
+	if res.BestObjective > 1e-3 {
+		t.Errorf("Cuckoo produced unexpected result on rastrigin, got %v", res.BestObjective)
+	}
+}
@@ -0,0 +1,376 @@
+package hego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// DEResult represents the result of the differential evolution algorithm
+type DEResult struct {
+	BestCandidates    [][]float64
+	BestObjectives    []float64
+	AverageObjectives []float64
+	BestCandidate     []float64
+	BestObjective     float64
+	Result
+}
+
+// DEStrategy encodes how mutant vectors are created in differential evolution
+type DEStrategy int
+
+const (
+	// RandOneBin is DE/rand/1/bin: v = x_r1 + F (x_r2 - x_r3)
+	RandOneBin DEStrategy = iota
+	// BestOneBin is DE/best/1/bin: v = x_best + F (x_r1 - x_r2)
+	BestOneBin
+	// CurrentToBestOneBin is DE/current-to-best/1/bin:
+	// v = x_i + F (x_best - x_i) + F (x_r1 - x_r2)
+	CurrentToBestOneBin
+)
+
+// DEAdaptation encodes how F and CR are controlled during the run
+type DEAdaptation int
+
+const (
+	// FixedParameters uses F and CR from the settings for all individuals
+	FixedParameters DEAdaptation = iota
+	// JDE lets every individual carry its own F and CR, which are resampled
+	// with probability 0.1 and survive with successful trial vectors (Brest et al.)
+	JDE
+	// SHADE is success-history based adaptive DE (Tanabe and Fukunaga). It
+	// samples F and CR around a memory of parameters that produced
+	// improvements, weighted by the size of the improvement. Mutants are always
+	// built with current-to-pbest/1 and an archive of replaced candidates
+	SHADE
+)
+
+// jdeResampleRate is the probability to resample F and CR of an individual in jDE
+const jdeResampleRate = 0.1
+
+// shadeMaxGreediness is the upper limit of the fraction of best candidates
+// SHADE chooses pbest from
+const shadeMaxGreediness = 0.2
+
+// DESettings represents settings for the differential evolution algorithm
+type DESettings struct {
+	// PopulationSize is the number of candidates, at least 4
+	PopulationSize int
+	// F is the differential weight in (0, 2]. With JDE it is the initial weight
+	F float64
+	// CR is the crossover probability in [0, 1]. With JDE it is the initial probability
+	CR float64
+	// Strategy is the mutation strategy. It is ignored with SHADE, which uses
+	// current-to-pbest/1
+	Strategy DEStrategy
+	// Adaptation controls F and CR during the run
+	Adaptation DEAdaptation
+	// MemorySize is the size of the success history of SHADE. When 0,
+	// PopulationSize is used
+	MemorySize int
+	Bounds
+	Settings
+}
+
+// Verify checks the validity of the settings and returns nil if everything is ok
+func (s *DESettings) Verify() error {
+	if s.PopulationSize < 4 {
+		return fmt.Errorf("population size must be at least 4, got %v", s.PopulationSize)
+	}
+	if s.Strategy < RandOneBin || s.Strategy > CurrentToBestOneBin {
+		return fmt.Errorf("unknown strategy %v", s.Strategy)
+	}
+	if s.Adaptation < FixedParameters || s.Adaptation > SHADE {
+		return fmt.Errorf("unknown adaptation %v", s.Adaptation)
+	}
+	if s.Adaptation != SHADE {
+		if s.F <= 0.0 || s.F > 2.0 {
+			return fmt.Errorf("F must be in (0, 2], got %v", s.F)
+		}
+		if s.CR < 0.0 || s.CR > 1.0 {
+			return fmt.Errorf("CR must be in [0, 1], got %v", s.CR)
+		}
+	}
+	if s.MemorySize < 0 {
+		return fmt.Errorf("memory size must not be negative, got %v", s.MemorySize)
+	}
+	return s.Bounds.Verify()
+}
+
+// shadeMemory is the success history of SHADE
+type shadeMemory struct {
+	f, cr []float64
+	// next is the memory slot updated next
+	next int
+	// successful parameters of the current iteration and their improvements
+	successF, successCR, improvements []float64
+}
+
+// newShadeMemory returns a memory of size n initialized with 0.5
+func newShadeMemory(n int) *shadeMemory {
+	m := shadeMemory{f: make([]float64, n), cr: make([]float64, n)}
+	for i := range m.f {
+		m.f[i], m.cr[i] = 0.5, 0.5
+	}
+	return &m
+}
+
+// sample draws F from a cauchy and CR from a normal distribution around a
+// random memory slot
+func (m *shadeMemory) sample() (f, cr float64) {
+	r := rand.Intn(len(m.f))
+	cr = math.Max(0.0, math.Min(1.0, m.cr[r]+0.1*rand.NormFloat64()))
+	for f <= 0.0 {
+		f = m.f[r] + 0.1*math.Tan(math.Pi*(rand.Float64()-0.5))
+	}
+	return math.Min(f, 1.0), cr
+}
+
+// success records parameters that improved the objective by improvement
+func (m *shadeMemory) success(f, cr, improvement float64) {
+	m.successF = append(m.successF, f)
+	m.successCR = append(m.successCR, cr)
+	m.improvements = append(m.improvements, improvement)
+}
+
+// update moves the next memory slot to the weighted means of the successful
+// parameters, a Lehmer mean for F and an arithmetic mean for CR
+func (m *shadeMemory) update() {
+	if len(m.improvements) == 0 {
+		return
+	}
+	total := 0.0
+	for _, w := range m.improvements {
+		total += w
+	}
+	if total > 0.0 {
+		sumF, sumF2, sumCR := 0.0, 0.0, 0.0
+		for i, w := range m.improvements {
+			w /= total
+			sumF += w * m.successF[i]
+			sumF2 += w * m.successF[i] * m.successF[i]
+			sumCR += w * m.successCR[i]
+		}
+		m.f[m.next] = sumF2 / sumF
+		m.cr[m.next] = sumCR
+		m.next = (m.next + 1) % len(m.f)
+	}
+	m.successF, m.successCR, m.improvements = m.successF[:0], m.successCR[:0], m.improvements[:0]
+}
+
+// pbest returns a random candidate among the best p * len(order) candidates
+// for a random p in [2 / len(order), shadeMaxGreediness], at least the two
+// best. order holds the indizes of the candidates sorted by objective
+func pbest(order []int) int {
+	minP := 2.0 / float64(len(order))
+	p := minP + (shadeMaxGreediness-minP)*rand.Float64()
+	top := int(math.Max(2.0, math.Round(p*float64(len(order)))))
+	return order[rand.Intn(top)]
+}
+
+// archiveCandidate adds a copy of x to the archive of replaced candidates.
+// A full archive replaces a random entry
+func archiveCandidate(archive [][]float64, x []float64, size int) [][]float64 {
+	c := make([]float64, len(x))
+	copy(c, x)
+	if len(archive) < size {
+		return append(archive, c)
+	}
+	archive[rand.Intn(len(archive))] = c
+	return archive
+}
+
+// distinctIndizes returns k distinct random indizes in [0, n) that differ from exclude
+func distinctIndizes(n, k, exclude int) []int {
+	res := make([]int, 0, k)
+	for len(res) < k {
+		r := rand.Intn(n)
+		if r == exclude {
+			continue
+		}
+		unique := true
+		for _, v := range res {
+			if v == r {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// DE performs Differential Evolution for minimizing a real valued function
+// (objective). init is called to create every candidate of the initial
+// population. In every iteration each candidate competes against a trial
+// vector, the binomial crossover of the candidate and a mutant built from
+// scaled differences of other candidates. With SHADE, the second difference
+// vector may also come from an archive of candidates replaced by their trials
+func DE(
+	objective func(x []float64) float64,
+	init func() []float64,
+	settings DESettings) (res DEResult, err error) {
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return res, err
+	}
+	start := time.Now()
+	logger := newLogger("Differential Evolution", []string{"Iteration", "Population Mean", "Population Best"}, settings.Verbose, settings.MaxIterations)
+	// increase funcEvaluations counter for every call to objective
+	evaluate := func(x []float64) float64 {
+		res.FuncEvaluations++
+		return objective(x) + settings.penalty(x)
+	}
+	if settings.KeepHistory {
+		res.BestCandidates = make([][]float64, 0, settings.MaxIterations)
+		res.BestObjectives = make([]float64, 0, settings.MaxIterations)
+		res.AverageObjectives = make([]float64, 0, settings.MaxIterations)
+	}
+
+	// initialize population with per individual parameters for jDE
+	population := make([][]float64, settings.PopulationSize)
+	objs := make([]float64, settings.PopulationSize)
+	fs := make([]float64, settings.PopulationSize)
+	crs := make([]float64, settings.PopulationSize)
+	best := 0
+	for i := range population {
+		population[i] = init()
+		if err = settings.verifyDimension(len(population[i])); err != nil {
+			return res, err
+		}
+		settings.repair(population[i], nil)
+		objs[i] = evaluate(population[i])
+		fs[i], crs[i] = settings.F, settings.CR
+		if objs[i] < objs[best] {
+			best = i
+		}
+	}
+	memorySize := settings.MemorySize
+	if memorySize == 0 {
+		memorySize = settings.PopulationSize
+	}
+	memory := newShadeMemory(memorySize)
+	// archive holds candidates replaced by better trials for SHADE, at most
+	// one per individual
+	archive := make([][]float64, 0, settings.PopulationSize)
+	order := make([]int, settings.PopulationSize)
+	for i := range order {
+		order[i] = i
+	}
+
+	n := len(population[0])
+	trials := make([][]float64, settings.PopulationSize)
+	for i := range trials {
+		trials[i] = make([]float64, n)
+	}
+	for i := 0; i < settings.MaxIterations; i++ {
+		totalObj := 0.0
+		if settings.Adaptation == SHADE {
+			sort.Slice(order, func(a, b int) bool { return objs[order[a]] < objs[order[b]] })
+		}
+		// successful trial vectors replace their target immediately and take part
+		// in the mutation of the following candidates
+		for j, x := range population {
+			f, cr := fs[j], crs[j]
+			switch settings.Adaptation {
+			case JDE:
+				if rand.Float64() < jdeResampleRate {
+					f = 0.1 + 0.9*rand.Float64()
+				}
+				if rand.Float64() < jdeResampleRate {
+					cr = rand.Float64()
+				}
+			case SHADE:
+				f, cr = memory.sample()
+			}
+			trial := trials[j]
+			r := distinctIndizes(settings.PopulationSize, 3, j)
+			// current-to-pbest/1 of SHADE takes x_r2 from population and archive
+			var xp, x2 []float64
+			if settings.Adaptation == SHADE {
+				xp = population[pbest(order)]
+				for {
+					k := rand.Intn(settings.PopulationSize + len(archive))
+					if k >= settings.PopulationSize {
+						x2 = archive[k-settings.PopulationSize]
+						break
+					}
+					if k != j && k != r[0] {
+						x2 = population[k]
+						break
+					}
+				}
+			}
+			// the mutated dimension jrand makes sure the trial differs from x
+			jrand := rand.Intn(n)
+			for d := range trial {
+				if d != jrand && rand.Float64() >= cr {
+					trial[d] = x[d]
+					continue
+				}
+				if settings.Adaptation == SHADE {
+					trial[d] = x[d] + f*(xp[d]-x[d]) + f*(population[r[0]][d]-x2[d])
+					continue
+				}
+				switch settings.Strategy {
+				case RandOneBin:
+					trial[d] = population[r[0]][d] + f*(population[r[1]][d]-population[r[2]][d])
+				case BestOneBin:
+					trial[d] = population[best][d] + f*(population[r[0]][d]-population[r[1]][d])
+				case CurrentToBestOneBin:
+					trial[d] = x[d] + f*(population[best][d]-x[d]) + f*(population[r[0]][d]-population[r[1]][d])
+				}
+			}
+			settings.repair(trial, nil)
+			obj := evaluate(trial)
+			if obj <= objs[j] {
+				if settings.Adaptation == SHADE && obj < objs[j] {
+					memory.success(f, cr, objs[j]-obj)
+					archive = archiveCandidate(archive, x, settings.PopulationSize)
+				}
+				// the old vector is reused for the next trial
+				trials[j], population[j] = population[j], trial
+				objs[j] = obj
+				fs[j], crs[j] = f, cr
+			}
+			totalObj += objs[j]
+		}
+		if settings.Adaptation == SHADE {
+			memory.update()
+		}
+		for j := range objs {
+			if objs[j] < objs[best] {
+				best = j
+			}
+		}
+
+		if settings.KeepHistory {
+			candidate := make([]float64, n)
+			copy(candidate, population[best])
+			res.BestCandidates = append(res.BestCandidates, candidate)
+			res.BestObjectives = append(res.BestObjectives, objs[best])
+			res.AverageObjectives = append(res.AverageObjectives, totalObj/float64(settings.PopulationSize))
+		}
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(totalObj / float64(settings.PopulationSize)),
+			fmt.Sprint(objs[best]),
+		})
+	}
+	res.BestObjective = objs[best]
+	res.BestCandidate = make([]float64, n)
+	copy(res.BestCandidate, population[best])
+
+	res.Runtime = time.Since(start)
+	res.Iterations = settings.MaxIterations
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return res, nil
+}
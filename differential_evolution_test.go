@@ -0,0 +1,177 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVerifyDESettings(t *testing.T) {
+	settings := DESettings{PopulationSize: 3}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with population size 3")
+	}
+	settings.PopulationSize = 10
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with F = 0")
+	}
+	settings.F = 0.5
+	settings.CR = 1.5
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with CR > 1")
+	}
+	settings.CR = 0.9
+	settings.Strategy = DEStrategy(10)
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with unknown strategy")
+	}
+	settings.Strategy = BestOneBin
+	settings.Adaptation = DEAdaptation(10)
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with unknown adaptation")
+	}
+	settings.Adaptation = SHADE
+	settings.F, settings.CR = 0.0, 0.0
+	if err := settings.Verify(); err != nil {
+		t.Errorf("SHADE does not require F and CR, got: %v", err)
+	}
+}
+
+func TestDistinctIndizes(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		r := distinctIndizes(4, 3, 2)
+		seen := map[int]bool{}
+		for _, v := range r {
+			if v == 2 || seen[v] || v < 0 || v >= 4 {
+				t.Errorf("expected 3 distinct indizes without 2, got %v", r)
+			}
+			seen[v] = true
+		}
+	}
+}
+
+func TestShadeMemory(t *testing.T) {
+	m := newShadeMemory(2)
+	for i := 0; i < 100; i++ {
+		f, cr := m.sample()
+		if f <= 0.0 || f > 1.0 || cr < 0.0 || cr > 1.0 {
+			t.Errorf("sampled parameters out of range: F = %v, CR = %v", f, cr)
+		}
+	}
+	m.success(0.2, 0.1, 1.0)
+	m.success(0.8, 0.9, 3.0)
+	m.update()
+	// weights are 0.25 and 0.75
+	if math.Abs(m.cr[0]-0.7) > 1e-12 || math.Abs(m.f[0]-0.49/0.65) > 1e-12 {
+		t.Errorf("unexpected memory update: F = %v, CR = %v", m.f[0], m.cr[0])
+	}
+	if m.next != 1 || len(m.improvements) != 0 {
+		t.Error("expected memory to move to the next slot and clear the successes")
+	}
+}
+
+func TestPBest(t *testing.T) {
+	order := []int{3, 1, 0, 2, 4, 5, 6, 7, 8, 9}
+	for i := 0; i < 100; i++ {
+		// at most 20 % of 10 candidates, the best two
+		if p := pbest(order); p != 3 && p != 1 {
+			t.Errorf("expected one of the two best candidates, got %v", p)
+		}
+	}
+}
+
+func TestArchiveCandidate(t *testing.T) {
+	archive := [][]float64{}
+	x := []float64{1.0, 2.0}
+	archive = archiveCandidate(archive, x, 2)
+	x[0] = 3.0
+	archive = archiveCandidate(archive, x, 2)
+	if len(archive) != 2 || archive[0][0] != 1.0 || archive[1][0] != 3.0 {
+		t.Errorf("expected copies of both candidates in the archive, got %v", archive)
+	}
+	archive = archiveCandidate(archive, []float64{5.0, 5.0}, 2)
+	if len(archive) != 2 || (archive[0][0] != 5.0 && archive[1][0] != 5.0) {
+		t.Errorf("expected a full archive to replace an entry, got %v", archive)
+	}
+}
+
+func TestDE(t *testing.T) {
+	sphere := func(x []float64) float64 {
+		return x[0]*x[0] + x[1]*x[1] + x[2]*x[2]
+	}
+	rastrigin := func(x []float64) float64 {
+		res := 10.0 * float64(len(x))
+		for _, v := range x {
+			res += v*v - 10.0*math.Cos(2.0*math.Pi*v)
+		}
+		return res
+	}
+	init := func() []float64 {
+		x := make([]float64, 3)
+		for i := range x {
+			x[i] = -5.12 + 10.24*rand.Float64()
+		}
+		return x
+	}
+	settings := DESettings{}
+	_, err := DE(sphere, init, settings)
+	if err == nil {
+		t.Error("DE should fail with invalid settings")
+	}
+	settings.PopulationSize = 30
+	settings.F = 0.5
+	settings.CR = 0.9
+	settings.MaxIterations = 300
+	settings.KeepHistory = true
+	settings.Lower = []float64{-5.12, -5.12, -5.12}
+	settings.Upper = []float64{5.12, 5.12, 5.12}
+	for _, strategy := range []DEStrategy{RandOneBin, BestOneBin, CurrentToBestOneBin} {
+		for _, adaptation := range []DEAdaptation{FixedParameters, JDE, SHADE} {
+			settings.Strategy = strategy
+			settings.Adaptation = adaptation
+			res, err := DE(sphere, init, settings)
+			if err != nil {
+				t.Errorf("Unexpected error in DE: %v", err)
+			}
+			if len(res.BestObjectives) != settings.MaxIterations {
+				t.Fatalf("expected history for every iteration, got %v", len(res.BestObjectives))
+			}
+			for i := 1; i < len(res.BestObjectives); i++ {
+				if res.BestObjectives[i] > res.BestObjectives[i-1] {
+					t.Fatal("best objective of the population must not increase")
+				}
+			}
+			if res.BestObjective >= res.BestObjectives[0] {
+				t.Errorf("DE with strategy %v and adaptation %v did not improve the initial population", strategy, adaptation)
+			}
+			// best/1 with fixed parameters may stagnate before reaching the optimum,
+			// all other combinations reach it within machine precision
+			tolerance := 1e-4
+			if strategy == BestOneBin && adaptation == FixedParameters {
+				tolerance = 1e-1
+			}
+			if res.BestObjective > tolerance {
+				t.Errorf("DE with strategy %v and adaptation %v produced unexpected result, got %v", strategy, adaptation, res.BestObjective)
+			}
+		}
+	}
+	// the exploring rand/1 strategy escapes the local optima of multimodal
+	// functions. With fixed parameters it rarely ends in the best local optimum
+	// at 0.995
+	settings.Strategy = RandOneBin
+	settings.PopulationSize = 50
+	for _, adaptation := range []DEAdaptation{FixedParameters, JDE, SHADE} {
+		settings.Adaptation = adaptation
+		res, err := DE(rastrigin, init, settings)
+		if err != nil {
+			t.Errorf("Unexpected error in DE: %v", err)
+		}
+		tolerance := 1e-3
+		if adaptation == FixedParameters {
+			tolerance = 1.0
+		}
+		if res.BestObjective > tolerance {
+			t.Errorf("DE with adaptation %v produced unexpected result on rastrigin, got %v", adaptation, res.BestObjective)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package hego
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// DirectSearchResult represents the result of the derivative free local
+// search methods NelderMead and PatternSearch
+type DirectSearchResult struct {
+	BestCandidates [][]float64
+	BestObjectives []float64
+	BestCandidate  []float64
+	BestObjective  float64
+	// Converged is true when the search stopped because the tolerance was reached
+	Converged bool
+	Result
+}
+
+// coefficients of the Nelder-Mead simplex operations
+const (
+	nelderMeadReflection  = 1.0
+	nelderMeadExpansion   = 2.0
+	nelderMeadContraction = 0.5
+	nelderMeadShrink      = 0.5
+)
+
+// NelderMeadSettings represents settings for the Nelder-Mead simplex method
+type NelderMeadSettings struct {
+	// InitialStep is the edge length of the initial simplex around x0
+	InitialStep float64
+	// Tolerance stops the search when the objective values of the simplex
+	// vertices and their distance to the best vertex fall below it
+	Tolerance float64
+	Bounds
+	Settings
+}
+
+// Verify checks the validity of the settings and returns nil if everything is ok
+func (s *NelderMeadSettings) Verify() error {
+	if s.InitialStep <= 0.0 {
+		return fmt.Errorf("initial step must be greater than 0, got %v", s.InitialStep)
+	}
+	if s.Tolerance < 0.0 {
+		return fmt.Errorf("tolerance must not be negative, got %v", s.Tolerance)
+	}
+	if s.MaxIterations <= 0 {
+		return fmt.Errorf("max iterations must be greater than 0, got %v", s.MaxIterations)
+	}
+	return s.Bounds.Verify()
+}
+
+// PatternMethod encodes the variant of pattern search
+type PatternMethod int
+
+const (
+	// CompassSearch polls the positive and negative coordinate directions and
+	// moves to the first improvement
+	CompassSearch PatternMethod = iota
+	// HookeJeeves explores the coordinate directions and repeats successful
+	// moves with pattern steps that accelerate along the valley
+	HookeJeeves
+)
+
+// PatternSearchSettings represents settings for pattern search
+type PatternSearchSettings struct {
+	// Method is the variant of pattern search
+	Method PatternMethod
+	// InitialStep is the initial step length in every coordinate
+	InitialStep float64
+	// Contraction is the factor the step length is reduced by when no
+	// improving step was found. When 0, the step is halved
+	Contraction float64
+	// Tolerance stops the search when the step length falls below it
+	Tolerance float64
+	Bounds
+	Settings
+}
+
+// Verify checks the validity of the settings and returns nil if everything is ok
+func (s *PatternSearchSettings) Verify() error {
+	if s.Method < CompassSearch || s.Method > HookeJeeves {
+		return fmt.Errorf("unknown pattern search method %v", s.Method)
+	}
+	if s.InitialStep <= 0.0 {
+		return fmt.Errorf("initial step must be greater than 0, got %v", s.InitialStep)
+	}
+	if s.Contraction < 0.0 || s.Contraction >= 1.0 {
+		return fmt.Errorf("contraction must be in [0, 1), got %v", s.Contraction)
+	}
+	if s.Tolerance < 0.0 {
+		return fmt.Errorf("tolerance must not be negative, got %v", s.Tolerance)
+	}
+	if s.MaxIterations <= 0 {
+		return fmt.Errorf("max iterations must be greater than 0, got %v", s.MaxIterations)
+	}
+	return s.Bounds.Verify()
+}
+
+// record updates the best candidate and the history of the result
+func (res *DirectSearchResult) record(x []float64, obj float64, keepHistory bool) {
+	if obj < res.BestObjective {
+		res.BestObjective = obj
+		copy(res.BestCandidate, x)
+	}
+	if keepHistory {
+		candidate := make([]float64, len(res.BestCandidate))
+		copy(candidate, res.BestCandidate)
+		res.BestCandidates = append(res.BestCandidates, candidate)
+		res.BestObjectives = append(res.BestObjectives, res.BestObjective)
+	}
+}
+
+// NelderMead performs the Nelder-Mead simplex method for minimizing a real
+// valued function (objective) from a starting point x0. The simplex of n + 1
+// vertices is reflected, expanded, contracted and shrunk until it collapses
+// around a local minimum. It is deterministic and well suited to polish the
+// result of a global search like PSO or ES
+func NelderMead(
+	objective func(x []float64) float64,
+	x0 []float64,
+	settings NelderMeadSettings) (res DirectSearchResult, err error) {
+	err = settings.Verify()
+	if err == nil {
+		err = settings.verifyDimension(len(x0))
+	}
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return res, err
+	}
+	start := time.Now()
+	logger := newLogger("Nelder-Mead", []string{"Iteration", "Best", "Worst"}, settings.Verbose, settings.MaxIterations)
+	// increase funcEvaluations counter for every call to objective
+	evaluate := func(x []float64) float64 {
+		res.FuncEvaluations++
+		return objective(x) + settings.penalty(x)
+	}
+	n := len(x0)
+	res.BestObjective = math.MaxFloat64
+	res.BestCandidate = make([]float64, n)
+
+	// the initial simplex is x0 and one step along every coordinate
+	simplex := make([][]float64, n+1)
+	objs := make([]float64, n+1)
+	for i := range simplex {
+		simplex[i] = make([]float64, n)
+		copy(simplex[i], x0)
+		if i > 0 {
+			simplex[i][i-1] += settings.InitialStep
+		}
+		settings.repair(simplex[i], nil)
+		objs[i] = evaluate(simplex[i])
+	}
+	// point returns c + coef * (x - c) within the bounds
+	point := func(c, x []float64, coef float64) []float64 {
+		p := make([]float64, n)
+		for d := range p {
+			p[d] = c[d] + coef*(x[d]-c[d])
+		}
+		settings.repair(p, nil)
+		return p
+	}
+	// replaceWorst replaces the worst vertex
+	replaceWorst := func(x []float64, obj float64) {
+		simplex[n], objs[n] = x, obj
+	}
+
+	order := make([]int, n+1)
+	i := 0
+	for ; i < settings.MaxIterations; i++ {
+		// sort vertices from best to worst
+		for k := range order {
+			order[k] = k
+		}
+		sort.SliceStable(order, func(a, b int) bool { return objs[order[a]] < objs[order[b]] })
+		sortedSimplex, sortedObjs := make([][]float64, n+1), make([]float64, n+1)
+		for k, o := range order {
+			sortedSimplex[k], sortedObjs[k] = simplex[o], objs[o]
+		}
+		simplex, objs = sortedSimplex, sortedObjs
+
+		res.record(simplex[0], objs[0], settings.KeepHistory)
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(objs[0]),
+			fmt.Sprint(objs[n]),
+		})
+		if objs[n]-objs[0] <= settings.Tolerance && simplexSize(simplex) <= settings.Tolerance {
+			res.Converged = true
+			break
+		}
+
+		// centroid of all vertices but the worst
+		centroid := make([]float64, n)
+		for _, v := range simplex[:n] {
+			for d := range centroid {
+				centroid[d] += v[d] / float64(n)
+			}
+		}
+
+		reflected := point(centroid, simplex[n], -nelderMeadReflection)
+		fr := evaluate(reflected)
+		switch {
+		case fr < objs[0]:
+			expanded := point(centroid, reflected, nelderMeadExpansion)
+			if fe := evaluate(expanded); fe < fr {
+				replaceWorst(expanded, fe)
+			} else {
+				replaceWorst(reflected, fr)
+			}
+			continue
+		case fr < objs[n-1]:
+			replaceWorst(reflected, fr)
+			continue
+		case fr < objs[n]:
+			// outside contraction
+			contracted := point(centroid, reflected, nelderMeadContraction)
+			if fc := evaluate(contracted); fc <= fr {
+				replaceWorst(contracted, fc)
+				continue
+			}
+		default:
+			// inside contraction
+			contracted := point(centroid, simplex[n], nelderMeadContraction)
+			if fc := evaluate(contracted); fc < objs[n] {
+				replaceWorst(contracted, fc)
+				continue
+			}
+		}
+		// shrink all vertices towards the best vertex
+		for k := 1; k <= n; k++ {
+			simplex[k] = point(simplex[0], simplex[k], nelderMeadShrink)
+			objs[k] = evaluate(simplex[k])
+		}
+	}
+
+	res.Runtime = time.Since(start)
+	res.Iterations = i
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return res, nil
+}
+
+// simplexSize returns the largest distance of a vertex to the first vertex
+func simplexSize(simplex [][]float64) float64 {
+	size := 0.0
+	for _, v := range simplex[1:] {
+		dist := 0.0
+		for d := range v {
+			dist += (v[d] - simplex[0][d]) * (v[d] - simplex[0][d])
+		}
+		size = math.Max(size, math.Sqrt(dist))
+	}
+	return size
+}
+
+// PatternSearch performs compass or Hooke-Jeeves pattern search for minimizing
+// a real valued function (objective) from a starting point x0. It polls the
+// coordinate directions with a step length that is contracted whenever no
+// improvement is found. It is deterministic and well suited to polish the
+// result of a global search like PSO or ES
+func PatternSearch(
+	objective func(x []float64) float64,
+	x0 []float64,
+	settings PatternSearchSettings) (res DirectSearchResult, err error) {
+	err = settings.Verify()
+	if err == nil {
+		err = settings.verifyDimension(len(x0))
+	}
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return res, err
+	}
+	start := time.Now()
+	logger := newLogger("Pattern Search", []string{"Iteration", "Best", "Step"}, settings.Verbose, settings.MaxIterations)
+	// increase funcEvaluations counter for every call to objective
+	evaluate := func(x []float64) float64 {
+		res.FuncEvaluations++
+		return objective(x) + settings.penalty(x)
+	}
+	contraction := settings.Contraction
+	if contraction == 0.0 {
+		contraction = 0.5
+	}
+	n := len(x0)
+	res.BestObjective = math.MaxFloat64
+	res.BestCandidate = make([]float64, n)
+
+	// explore polls the coordinate directions around x with the given step. It
+	// returns the improved point and its objective value. Compass search stops
+	// at the first improvement, Hooke-Jeeves continues with the next coordinate
+	// from the improved point
+	explore := func(x []float64, obj, step float64) ([]float64, float64) {
+		current := make([]float64, n)
+		copy(current, x)
+		for d := 0; d < n; d++ {
+			improved := false
+			for _, sign := range []float64{1.0, -1.0} {
+				trial := make([]float64, n)
+				copy(trial, current)
+				trial[d] += sign * step
+				settings.repair(trial, nil)
+				if trialObj := evaluate(trial); trialObj < obj {
+					current, obj = trial, trialObj
+					improved = true
+					break
+				}
+			}
+			if improved && settings.Method == CompassSearch {
+				break
+			}
+		}
+		return current, obj
+	}
+
+	base := make([]float64, n)
+	copy(base, x0)
+	settings.repair(base, nil)
+	baseObj := evaluate(base)
+	step := settings.InitialStep
+	// previous is the base before the last successful move, used for pattern moves
+	var previous []float64
+	i := 0
+	for ; i < settings.MaxIterations; i++ {
+		res.record(base, baseObj, settings.KeepHistory)
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(baseObj),
+			fmt.Sprint(step),
+		})
+		if step < settings.Tolerance {
+			res.Converged = true
+			break
+		}
+		if previous != nil {
+			// the pattern move repeats the last successful move and explores
+			// around the extrapolated point
+			pattern := make([]float64, n)
+			for d := range pattern {
+				pattern[d] = 2.0*base[d] - previous[d]
+			}
+			settings.repair(pattern, nil)
+			next, nextObj := explore(pattern, evaluate(pattern), step)
+			if nextObj < baseObj {
+				previous, base, baseObj = base, next, nextObj
+			} else {
+				previous = nil
+			}
+			continue
+		}
+		next, nextObj := explore(base, baseObj, step)
+		if nextObj >= baseObj {
+			step *= contraction
+			continue
+		}
+		if settings.Method == HookeJeeves {
+			previous = base
+		}
+		base, baseObj = next, nextObj
+	}
+
+	res.Runtime = time.Since(start)
+	res.Iterations = i
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return res, nil
+}
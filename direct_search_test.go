@@ -0,0 +1,136 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// rosenbrock has a curved valley with the minimum 0 at (1, 1)
+func rosenbrock(x []float64) float64 {
+	return 100.0*math.Pow(x[1]-x[0]*x[0], 2) + math.Pow(1.0-x[0], 2)
+}
+
+func TestVerifyDirectSearchSettings(t *testing.T) {
+	nm := NelderMeadSettings{}
+	if nm.Verify() == nil {
+		t.Error("expected verification to fail without initial step")
+	}
+	nm.InitialStep = 1.0
+	if nm.Verify() == nil {
+		t.Error("expected verification to fail without max iterations")
+	}
+	nm.MaxIterations = 10
+	if err := nm.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+	ps := PatternSearchSettings{InitialStep: 1.0, Contraction: 1.0}
+	ps.MaxIterations = 10
+	if ps.Verify() == nil {
+		t.Error("expected verification to fail with contraction 1")
+	}
+	ps.Contraction = 0.0
+	ps.Method = PatternMethod(10)
+	if ps.Verify() == nil {
+		t.Error("expected verification to fail with unknown method")
+	}
+	ps.Method = HookeJeeves
+	if err := ps.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+}
+
+func TestNelderMead(t *testing.T) {
+	settings := NelderMeadSettings{}
+	_, err := NelderMead(rosenbrock, []float64{-1.2, 1.0}, settings)
+	if err == nil {
+		t.Error("NelderMead should fail with invalid settings")
+	}
+	settings.InitialStep = 0.5
+	settings.Tolerance = 1e-10
+	settings.MaxIterations = 2000
+	settings.KeepHistory = true
+	res, err := NelderMead(rosenbrock, []float64{-1.2, 1.0}, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in NelderMead: %v", err)
+	}
+	if !res.Converged || res.Iterations >= settings.MaxIterations {
+		t.Errorf("expected NelderMead to converge early, stopped after %v iterations", res.Iterations)
+	}
+	if math.Abs(res.BestCandidate[0]-1.0) > 1e-4 || math.Abs(res.BestCandidate[1]-1.0) > 1e-4 {
+		t.Errorf("NelderMead produced unexpected result, got %v", res.BestCandidate)
+	}
+	if len(res.BestObjectives) != res.Iterations+1 {
+		t.Errorf("expected history for every iteration, got %v", len(res.BestObjectives))
+	}
+	again, _ := NelderMead(rosenbrock, []float64{-1.2, 1.0}, settings)
+	if again.BestObjective != res.BestObjective || again.FuncEvaluations != res.FuncEvaluations {
+		t.Error("expected NelderMead to be deterministic")
+	}
+}
+
+func TestPatternSearch(t *testing.T) {
+	settings := PatternSearchSettings{}
+	_, err := PatternSearch(rosenbrock, []float64{-1.2, 1.0}, settings)
+	if err == nil {
+		t.Error("PatternSearch should fail with invalid settings")
+	}
+	settings.InitialStep = 0.5
+	settings.Tolerance = 1e-8
+	settings.MaxIterations = 100000
+	evaluations := map[PatternMethod]int{}
+	for _, method := range []PatternMethod{CompassSearch, HookeJeeves} {
+		settings.Method = method
+		res, err := PatternSearch(rosenbrock, []float64{-1.2, 1.0}, settings)
+		if err != nil {
+			t.Errorf("Unexpected error in PatternSearch: %v", err)
+		}
+		if !res.Converged {
+			t.Errorf("expected method %v to converge", method)
+		}
+		if res.BestObjective > 1e-6 {
+			t.Errorf("PatternSearch with method %v produced unexpected result, got %v", method, res.BestObjective)
+		}
+		evaluations[method] = res.FuncEvaluations
+	}
+	if evaluations[HookeJeeves] >= evaluations[CompassSearch] {
+		t.Errorf("expected pattern moves to speed up the search in the valley, got %v", evaluations)
+	}
+}
+
+func TestPolishPSOResult(t *testing.T) {
+	init := func() ([]float64, []float64) {
+		return []float64{-2.0 + 4.0*rand.Float64(), -2.0 + 4.0*rand.Float64()}, []float64{rand.Float64(), rand.Float64()}
+	}
+	pso := PSOSettings{PopulationSize: 10, LearningRate: 1.0, Omega: 0.7, ParticleWeight: 1.0, GlobalWeight: 1.0}
+	pso.MaxIterations = 20
+	global, err := PSO(rosenbrock, init, pso)
+	if err != nil {
+		t.Errorf("Unexpected error in PSO: %v", err)
+	}
+	settings := NelderMeadSettings{InitialStep: 0.1, Tolerance: 1e-12}
+	settings.MaxIterations = 2000
+	local, err := NelderMead(rosenbrock, global.BestParticle, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in NelderMead: %v", err)
+	}
+	if local.BestObjective > global.BestObjective || local.BestObjective > 1e-8 {
+		t.Errorf("expected NelderMead to polish the PSO result %v, got %v", global.BestObjective, local.BestObjective)
+	}
+}
+
+func TestDirectSearchBounds(t *testing.T) {
+	settings := PatternSearchSettings{InitialStep: 0.5, Tolerance: 1e-8}
+	settings.MaxIterations = 1000
+	settings.Lower = []float64{2.0, 2.0}
+	settings.Upper = []float64{3.0, 3.0}
+	res, err := PatternSearch(rosenbrock, []float64{2.5, 2.5}, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in PatternSearch: %v", err)
+	}
+	for _, v := range res.BestCandidate {
+		if v < 2.0 || v > 3.0 {
+			t.Errorf("expected result within bounds, got %v", res.BestCandidate)
+		}
+	}
+}
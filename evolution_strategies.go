@@ -30,6 +30,15 @@ type ESSettings struct {
 	// NoiseSigma is the sigma value for noise generated. A higher sigma results in a wider
 	// search spread, but might result in inaccuracies for the gradient estimate
 	NoiseSigma float64
+	// Antithetic samples mirrored pairs of noise vectors (+e, -e), which reduces the
+	// variance of the gradient estimate. PopulationSize must be even
+	Antithetic bool
+	// FitnessShaping replaces the standardized rewards with rank based
+	// utilities, which makes the search invariant to the scale of the objective
+	FitnessShaping bool
+	// Optimizer performs the gradient steps. When nil, plain gradient descent is used
+	Optimizer ESOptimizer
+	Bounds
 	Settings
 }
 
@@ -44,7 +53,15 @@ func (s *ESSettings) Verify() error {
 	if s.NoiseSigma == 0.0 {
 		return errors.New("sigma = 0.0 leads to no search at all")
 	}
-	return nil
+	if s.Antithetic && s.PopulationSize%2 != 0 {
+		return fmt.Errorf("antithetic sampling requires an even population size, got %v", s.PopulationSize)
+	}
+	if s.Optimizer != nil {
+		if err := s.Optimizer.Verify(); err != nil {
+			return err
+		}
+	}
+	return s.Bounds.Verify()
 }
 
 // ES performs Evolutionary Strategy algorithm suited for minimizing
@@ -56,6 +73,9 @@ func ES(
 	x0 []float64,
 	settings ESSettings) (res ESResult, err error) {
 	err = settings.Verify()
+	if err == nil {
+		err = settings.verifyDimension(len(x0))
+	}
 	if err != nil {
 		err = fmt.Errorf("settings verification failed: %v", err)
 		return res, err
@@ -65,7 +85,7 @@ func ES(
 	// increase funcEvaluations counter for every call to objective
 	evaluate := func(x []float64) float64 {
 		res.FuncEvaluations++
-		return objective(x)
+		return objective(x) + settings.penalty(x)
 	}
 	// write noise into x
 	initNoise := func(x []float64) {
@@ -73,15 +93,15 @@ func ES(
 			x[i] = rand.NormFloat64() * settings.NoiseSigma
 		}
 	}
-	// add x to noise vector
-	combineWithNoise := func(noise, x []float64) {
-		for i := range noise {
-			noise[i] += x[i]
-		}
+	optimizer := settings.Optimizer
+	if optimizer == nil {
+		optimizer = &SGD{}
 	}
+	optimizer.Init(len(x0))
 
 	candidate := make([]float64, len(x0))
 	copy(candidate, x0)
+	settings.repair(candidate, nil)
 
 	if settings.KeepHistory {
 		res.BestObjectives = make([]float64, settings.MaxIterations)
@@ -92,48 +112,81 @@ func ES(
 	res.BestObjective = math.MaxFloat64
 	res.BestCandidate = make([]float64, len(x0))
 
-	// initialize memory for population and their rewards
+	// initialize memory for population, their noise and rewards
 	population := make([][]float64, settings.PopulationSize)
+	noise := make([][]float64, settings.PopulationSize)
 	for i := range population {
 		population[i] = make([]float64, len(x0))
+		noise[i] = make([]float64, len(x0))
 	}
 	rewards := make([]float64, settings.PopulationSize)
+	gradient := make([]float64, len(x0))
 
 	for i := 0; i < settings.MaxIterations; i++ {
 
 		totalReward := 0.0
-		bestReward := math.MaxFloat64
+		best := 0
 		for j := range population {
-			// create new candidate with noise
-			initNoise(population[j])
-			combineWithNoise(population[j], candidate)
-			reward := evaluate(population[j])
-			rewards[j] = reward
-			totalReward += reward
-			if reward < bestReward {
-				bestReward = reward
+			// create new candidate with noise, antithetic pairs share the noise
+			if settings.Antithetic && j%2 == 1 {
+				for d, e := range noise[j-1] {
+					noise[j][d] = -e
+				}
+			} else {
+				initNoise(noise[j])
+			}
+			for d, e := range noise[j] {
+				population[j][d] = candidate[d] + e
+			}
+			if settings.bounded() {
+				settings.repair(population[j], nil)
+				// the gradient estimate uses the noise that was actually applied
+				for d := range noise[j] {
+					noise[j][d] = population[j][d] - candidate[d]
+				}
+			}
+			rewards[j] = evaluate(population[j])
+			totalReward += rewards[j]
+			if rewards[j] < rewards[best] {
+				best = j
 			}
 		}
-
-		// compute standart deviation of rewards
 		meanReward := totalReward / float64(settings.PopulationSize)
+		bestReward := rewards[best]
+
+		// the gradient is estimated from standardized rewards or utilities
+		weights := rewards
+		if settings.FitnessShaping {
+			// utilities are largest for the best rewards, but the gradient points
+			// towards higher objective values
+			weights = rankUtilities(rewards)
+			for j := range weights {
+				weights[j] = -weights[j]
+			}
+		}
+		mean := 0.0
+		for _, w := range weights {
+			mean += w
+		}
+		mean /= float64(settings.PopulationSize)
 		stdDev := 0.0
-		for _, reward := range rewards {
-			stdDev += math.Pow(reward-meanReward, 2)
+		for _, w := range weights {
+			stdDev += math.Pow(w-mean, 2)
 		}
 		stdDev = math.Sqrt(stdDev / float64(settings.PopulationSize))
 
-		// update candidate
-		for j := range candidate {
-			// estimate gradient
-			gradientEstimate := 0.0
-			for index, individuum := range population {
-				gradientEstimate += individuum[j] * (rewards[index] - meanReward) / stdDev
+		// without variance in the rewards there is no information about the gradient
+		if stdDev > 0.0 {
+			for d := range gradient {
+				gradient[d] = 0.0
+				for j, e := range noise {
+					gradient[d] += e[d] * (weights[j] - mean) / stdDev
+				}
+				gradient[d] *= 1.0 / (float64(settings.PopulationSize) * settings.NoiseSigma)
 			}
-			gradientEstimate *= 1.0 / (float64(settings.PopulationSize) * settings.NoiseSigma)
-
 			// perform gradient step towards minimum
-			candidate[j] -= settings.LearningRate * gradientEstimate
+			optimizer.Step(candidate, gradient, settings.LearningRate)
+			settings.repair(candidate, nil)
 		}
 		// update result
 		if settings.KeepHistory {
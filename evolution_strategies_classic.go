@@ -0,0 +1,165 @@
+package hego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// MuLambdaESSettings represents settings for the classic (mu/rho +, lambda) evolution strategy
+type MuLambdaESSettings struct {
+	// Mu is the number of parents
+	Mu int
+	// Rho is the number of parents that are recombined into one offspring
+	Rho int
+	// Lambda is the number of offspring created in every iteration
+	Lambda int
+	// Plus selects the next parents from parents and offspring (mu + lambda). Otherwise
+	// only offspring are selected (mu, lambda), which requires Lambda >= Mu
+	Plus bool
+	// InitialSigma is the initial step size of every coordinate
+	InitialSigma float64
+	Bounds
+	Settings
+}
+
+// Verify checks the validity of the settings and returns nil if everything is ok
+func (s *MuLambdaESSettings) Verify() error {
+	if s.Mu < 1 {
+		return fmt.Errorf("mu must be at least 1, got %v", s.Mu)
+	}
+	if s.Rho < 1 || s.Rho > s.Mu {
+		return fmt.Errorf("rho must be between 1 and mu, got %v", s.Rho)
+	}
+	if s.Lambda < 1 {
+		return fmt.Errorf("lambda must be at least 1, got %v", s.Lambda)
+	}
+	if !s.Plus && s.Lambda < s.Mu {
+		return fmt.Errorf("comma selection requires lambda >= mu, got lambda = %v and mu = %v", s.Lambda, s.Mu)
+	}
+	if s.InitialSigma <= 0.0 {
+		return fmt.Errorf("initial sigma must be greater than 0, got %v", s.InitialSigma)
+	}
+	return s.Bounds.Verify()
+}
+
+// esIndividual is a candidate of the classic evolution strategy with its own step sizes
+type esIndividual struct {
+	x         []float64
+	sigma     []float64
+	objective float64
+}
+
+// MuLambdaES performs the classic (mu/rho +, lambda) Evolution Strategy for
+// minimizing a real valued function (objective) from a starting point x0.
+// Every offspring is the intermediate recombination of Rho random parents and
+// mutated with its own per coordinate step sizes. The step sizes are
+// self-adapted with log-normal mutations, so successful step sizes are
+// inherited together with successful positions
+func MuLambdaES(
+	objective func(x []float64) float64,
+	x0 []float64,
+	settings MuLambdaESSettings) (res ESResult, err error) {
+	err = settings.Verify()
+	if err == nil {
+		err = settings.verifyDimension(len(x0))
+	}
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return res, err
+	}
+	start := time.Now()
+	logger := newLogger("Evolution Strategy (mu/rho +, lambda)", []string{"Iteration", "Offspring Mean", "Best Parent"}, settings.Verbose, settings.MaxIterations)
+	// increase funcEvaluations counter for every call to objective
+	evaluate := func(x []float64) float64 {
+		res.FuncEvaluations++
+		return objective(x) + settings.penalty(x)
+	}
+	n := len(x0)
+	// learning rates of the log-normal self-adaptation
+	tauGlobal := 1.0 / math.Sqrt(2.0*float64(n))
+	tauLocal := 1.0 / math.Sqrt(2.0*math.Sqrt(float64(n)))
+
+	if settings.KeepHistory {
+		res.BestObjectives = make([]float64, settings.MaxIterations)
+		res.AverageObjectives = make([]float64, settings.MaxIterations)
+		res.Candidates = make([][]float64, settings.MaxIterations)
+	}
+	res.BestObjective = math.MaxFloat64
+	res.BestCandidate = make([]float64, n)
+
+	// the first parent is x0, the others are spread around it
+	parents := make([]esIndividual, settings.Mu)
+	for i := range parents {
+		parents[i] = esIndividual{x: make([]float64, n), sigma: make([]float64, n)}
+		for d := range x0 {
+			parents[i].x[d] = x0[d]
+			if i > 0 {
+				parents[i].x[d] += settings.InitialSigma * rand.NormFloat64()
+			}
+			parents[i].sigma[d] = settings.InitialSigma
+		}
+		settings.repair(parents[i].x, nil)
+		parents[i].objective = evaluate(parents[i].x)
+	}
+
+	offspring := make([]esIndividual, settings.Lambda)
+	for i := 0; i < settings.MaxIterations; i++ {
+		totalObj := 0.0
+		for k := range offspring {
+			child := esIndividual{x: make([]float64, n), sigma: make([]float64, n)}
+			// intermediate recombination of rho distinct parents
+			for _, p := range rand.Perm(settings.Mu)[:settings.Rho] {
+				for d := range child.x {
+					child.x[d] += parents[p].x[d] / float64(settings.Rho)
+					child.sigma[d] += parents[p].sigma[d] / float64(settings.Rho)
+				}
+			}
+			// self-adaptation of the step sizes, then mutation of the position
+			global := tauGlobal * rand.NormFloat64()
+			for d := range child.x {
+				child.sigma[d] *= math.Exp(global + tauLocal*rand.NormFloat64())
+				child.x[d] += child.sigma[d] * rand.NormFloat64()
+			}
+			settings.repair(child.x, nil)
+			child.objective = evaluate(child.x)
+			totalObj += child.objective
+			offspring[k] = child
+		}
+
+		// select the mu best of offspring (comma) or of parents and offspring (plus)
+		pool := offspring
+		if settings.Plus {
+			pool = append(append(make([]esIndividual, 0, settings.Mu+settings.Lambda), parents...), offspring...)
+		}
+		sort.SliceStable(pool, func(a, b int) bool { return pool[a].objective < pool[b].objective })
+		copy(parents, pool[:settings.Mu])
+
+		best := parents[0]
+		if best.objective < res.BestObjective {
+			res.BestObjective = best.objective
+			copy(res.BestCandidate, best.x)
+		}
+		if settings.KeepHistory {
+			res.Candidates[i] = make([]float64, n)
+			copy(res.Candidates[i], best.x)
+			res.BestObjectives[i] = best.objective
+			res.AverageObjectives[i] = totalObj / float64(settings.Lambda)
+		}
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(totalObj / float64(settings.Lambda)),
+			fmt.Sprint(best.objective),
+		})
+	}
+
+	res.Runtime = time.Since(start)
+	res.Iterations = settings.MaxIterations
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package hego
+
+import (
+	"testing"
+)
+
+func TestVerifyMuLambdaESSettings(t *testing.T) {
+	settings := MuLambdaESSettings{}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with mu 0")
+	}
+	settings.Mu = 5
+	settings.Rho = 6
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with rho > mu")
+	}
+	settings.Rho = 2
+	settings.Lambda = 3
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with comma selection and lambda < mu")
+	}
+	settings.Plus = true
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail without initial sigma")
+	}
+	settings.InitialSigma = 1.0
+	if err := settings.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+}
+
+func TestMuLambdaES(t *testing.T) {
+	// badly scaled sphere, requires different step sizes per coordinate
+	f := func(x []float64) float64 {
+		return x[0]*x[0] + 1000.0*x[1]*x[1] + 10.0*x[2]*x[2]
+	}
+	x0 := []float64{5.0, 1.0, -3.0}
+	settings := MuLambdaESSettings{}
+	_, err := MuLambdaES(f, x0, settings)
+	if err == nil {
+		t.Error("MuLambdaES should fail with invalid settings")
+	}
+	settings.Mu = 5
+	settings.Rho = 2
+	settings.Lambda = 35
+	settings.InitialSigma = 1.0
+	settings.MaxIterations = 300
+	settings.KeepHistory = true
+	for _, plus := range []bool{false, true} {
+		settings.Plus = plus
+		res, err := MuLambdaES(f, x0, settings)
+		if err != nil {
+			t.Errorf("Unexpected error in MuLambdaES: %v", err)
+		}
+		if res.BestObjective > 1e-6 {
+			t.Errorf("MuLambdaES with plus = %v produced unexpected result, got %v", plus, res.BestObjective)
+		}
+		if len(res.BestObjectives) != settings.MaxIterations {
+			t.Errorf("expected history for every iteration, got %v", len(res.BestObjectives))
+		}
+		if res.FuncEvaluations != settings.Mu+settings.Lambda*settings.MaxIterations {
+			t.Errorf("unexpected number of evaluations %v", res.FuncEvaluations)
+		}
+	}
+}
+
+func TestMuLambdaESPlusIsElitist(t *testing.T) {
+	f := func(x []float64) float64 {
+		return x[0] * x[0]
+	}
+	settings := MuLambdaESSettings{Mu: 2, Rho: 1, Lambda: 4, Plus: true, InitialSigma: 5.0}
+	settings.MaxIterations = 50
+	settings.KeepHistory = true
+	res, err := MuLambdaES(f, []float64{3.0}, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in MuLambdaES: %v", err)
+	}
+	for i := 1; i < len(res.BestObjectives); i++ {
+		if res.BestObjectives[i] > res.BestObjectives[i-1] {
+			t.Errorf("plus selection must never lose the best parent, got %v after %v", res.BestObjectives[i], res.BestObjectives[i-1])
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package hego
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ESOptimizer turns the gradient estimates of ES into steps of the candidate
+type ESOptimizer interface {
+	// Init resets the state of the optimizer for an n dimensional problem
+	Init(n int)
+	// Verify checks the validity of the optimizer parameters and returns nil if everything is ok
+	Verify() error
+	// Step moves x in place against the gradient estimate
+	Step(x, gradient []float64, learningRate float64)
+}
+
+// SGD is stochastic gradient descent with momentum. The step is the
+// exponentially decaying sum of the previous steps and the current gradient
+type SGD struct {
+	// Momentum is the decay of the previous steps in [0, 1). 0 is plain gradient descent
+	Momentum float64
+	velocity []float64
+}
+
+// Init resets the velocity
+func (s *SGD) Init(n int) {
+	s.velocity = make([]float64, n)
+}
+
+// Verify checks the validity of the momentum
+func (s *SGD) Verify() error {
+	if s.Momentum < 0.0 || s.Momentum >= 1.0 {
+		return fmt.Errorf("momentum must be in [0, 1), got %v", s.Momentum)
+	}
+	return nil
+}
+
+// Step performs a gradient descent step with momentum
+func (s *SGD) Step(x, gradient []float64, learningRate float64) {
+	for i, g := range gradient {
+		s.velocity[i] = s.Momentum*s.velocity[i] + learningRate*g
+		x[i] -= s.velocity[i]
+	}
+}
+
+// Adam adapts the step size of every coordinate with running estimates of the
+// first and second moment of the gradient (Kingma and Ba). Zero values are
+// replaced by the common defaults Beta1 = 0.9, Beta2 = 0.999 and Epsilon = 1e-8
+type Adam struct {
+	Beta1   float64
+	Beta2   float64
+	Epsilon float64
+	m, v    []float64
+	t       int
+}
+
+// Init resets the moment estimates
+func (a *Adam) Init(n int) {
+	a.m = make([]float64, n)
+	a.v = make([]float64, n)
+	a.t = 0
+}
+
+// Verify checks the validity of the decay rates
+func (a *Adam) Verify() error {
+	if a.Beta1 < 0.0 || a.Beta1 >= 1.0 || a.Beta2 < 0.0 || a.Beta2 >= 1.0 {
+		return fmt.Errorf("decay rates must be in [0, 1), got %v and %v", a.Beta1, a.Beta2)
+	}
+	if a.Epsilon < 0.0 {
+		return fmt.Errorf("epsilon must not be negative, got %v", a.Epsilon)
+	}
+	return nil
+}
+
+// Step performs a bias corrected Adam step
+func (a *Adam) Step(x, gradient []float64, learningRate float64) {
+	beta1, beta2, epsilon := a.Beta1, a.Beta2, a.Epsilon
+	if beta1 == 0.0 {
+		beta1 = 0.9
+	}
+	if beta2 == 0.0 {
+		beta2 = 0.999
+	}
+	if epsilon == 0.0 {
+		epsilon = 1e-8
+	}
+	a.t++
+	for i, g := range gradient {
+		a.m[i] = beta1*a.m[i] + (1.0-beta1)*g
+		a.v[i] = beta2*a.v[i] + (1.0-beta2)*g*g
+		mHat := a.m[i] / (1.0 - math.Pow(beta1, float64(a.t)))
+		vHat := a.v[i] / (1.0 - math.Pow(beta2, float64(a.t)))
+		x[i] -= learningRate * mHat / (math.Sqrt(vHat) + epsilon)
+	}
+}
+
+// rankUtilities returns the fitness shaping utilities of Wierstra et al. for
+// the rewards. The best (lowest) reward receives the largest utility, the
+// utilities sum up to 0
+func rankUtilities(rewards []float64) []float64 {
+	n := len(rewards)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return rewards[order[i]] < rewards[order[j]] })
+	raw := make([]float64, n)
+	total := 0.0
+	for rank := range raw {
+		raw[rank] = math.Max(0.0, math.Log(float64(n)/2.0+1.0)-math.Log(float64(rank+1)))
+		total += raw[rank]
+	}
+	utilities := make([]float64, n)
+	for rank, index := range order {
+		utilities[index] = raw[rank]/total - 1.0/float64(n)
+	}
+	return utilities
+}
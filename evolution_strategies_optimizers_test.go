@@ -0,0 +1,57 @@
+package hego
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSGD(t *testing.T) {
+	s := &SGD{Momentum: 0.5}
+	if err := s.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+	s.Init(1)
+	x := []float64{0.0}
+	s.Step(x, []float64{1.0}, 0.1)
+	s.Step(x, []float64{1.0}, 0.1)
+	// second step is 0.5 * 0.1 + 0.1
+	if math.Abs(x[0]+0.25) > 1e-12 {
+		t.Errorf("expected x = -0.25 after two steps, got %v", x[0])
+	}
+	s.Momentum = 1.0
+	if s.Verify() == nil {
+		t.Error("expected verification to fail with momentum 1")
+	}
+}
+
+func TestAdam(t *testing.T) {
+	a := &Adam{}
+	if err := a.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+	a.Init(2)
+	x := []float64{0.0, 0.0}
+	a.Step(x, []float64{100.0, 0.01}, 0.1)
+	// the first bias corrected step has the size of the learning rate
+	if math.Abs(x[0]+0.1) > 1e-6 || math.Abs(x[1]+0.1) > 1e-4 {
+		t.Errorf("expected first step of -0.1 independent of gradient scale, got %v", x)
+	}
+	a.Beta2 = 1.5
+	if a.Verify() == nil {
+		t.Error("expected verification to fail with beta2 > 1")
+	}
+}
+
+func TestRankUtilities(t *testing.T) {
+	utilities := rankUtilities([]float64{3.0, 1.0, 100.0, 2.0})
+	total := 0.0
+	for _, u := range utilities {
+		total += u
+	}
+	if math.Abs(total) > 1e-12 {
+		t.Errorf("expected utilities to sum up to 0, got %v", total)
+	}
+	if !(utilities[1] > utilities[3] && utilities[3] > utilities[0] && utilities[0] >= utilities[2]) {
+		t.Errorf("expected utilities ordered by rank, got %v", utilities)
+	}
+}
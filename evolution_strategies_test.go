@@ -1,6 +1,7 @@
 package hego
 
 import (
+	"math"
 	"testing"
 )
 
@@ -62,3 +63,83 @@ func TestES(t *testing.T) {
 		t.Error("with KeepHistory set, bestObjectives should contain values")
 	}
 }
+
+func TestESBounds(t *testing.T) {
+	f := func(x []float64) float64 {
+		return x[0] * x[0]
+	}
+	settings := ESSettings{}
+	settings.MaxIterations = 100
+	settings.LearningRate = 0.5
+	settings.NoiseSigma = 0.1
+	settings.PopulationSize = 20
+	settings.Lower = []float64{1.0}
+	settings.Upper = []float64{5.0}
+	if _, err := ES(f, []float64{3.0, 3.0}, settings); err == nil {
+		t.Error("ES should fail when bounds do not match x0")
+	}
+	res, err := ES(f, []float64{3.0}, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in ES algorithm: %v", err)
+	}
+	if res.BestCandidate[0] < 1.0 || res.BestCandidate[0] > 1.5 {
+		t.Errorf("expected best candidate close to lower bound 1.0, got %v", res.BestCandidate[0])
+	}
+}
+
+func TestVerifyESVariants(t *testing.T) {
+	settings := ESSettings{LearningRate: 0.1, PopulationSize: 11, NoiseSigma: 0.5, Antithetic: true}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with antithetic sampling and odd population size")
+	}
+	settings.PopulationSize = 10
+	settings.Optimizer = &SGD{Momentum: -1.0}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with invalid optimizer")
+	}
+	settings.Optimizer = &Adam{}
+	if err := settings.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+}
+
+func TestESVariants(t *testing.T) {
+	f := func(x []float64) float64 {
+		return x[0]*x[0] + 100.0*x[1]*x[1]
+	}
+	settings := ESSettings{}
+	settings.MaxIterations = 300
+	settings.NoiseSigma = 0.1
+	settings.PopulationSize = 20
+	settings.Antithetic = true
+	settings.FitnessShaping = true
+	for _, optimizer := range []ESOptimizer{nil, &SGD{Momentum: 0.5}, &Adam{}} {
+		settings.Optimizer = optimizer
+		settings.LearningRate = 0.1
+		res, err := ES(f, []float64{3.0, 1.0}, settings)
+		if err != nil {
+			t.Errorf("Unexpected error in ES algorithm: %v", err)
+		}
+		if res.BestObjective > 0.5 {
+			t.Errorf("ES with optimizer %T produced unexpected result, got %v", optimizer, res.BestObjective)
+		}
+	}
+}
+
+func TestESZeroVariance(t *testing.T) {
+	f := func(x []float64) float64 {
+		return 1.0
+	}
+	settings := ESSettings{}
+	settings.MaxIterations = 5
+	settings.LearningRate = 0.1
+	settings.NoiseSigma = 0.1
+	settings.PopulationSize = 10
+	res, err := ES(f, []float64{3.0}, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in ES algorithm: %v", err)
+	}
+	if math.IsNaN(res.BestCandidate[0]) || res.BestCandidate[0] != 3.0 {
+		t.Errorf("expected candidate to stay at 3.0 without reward variance, got %v", res.BestCandidate[0])
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ccssmnn/hego"
+)
+
+func rastringin(v []float64) float64 {
+	x, y := v[0], v[1]
+	return 10*2 + (x*x - 10*math.Cos(2*math.Pi*x)) + (y*y - 10*math.Cos(2*math.Pi*y))
+}
+
+func main() {
+
+	x0 := []float64{rand.Float64()*10.0 - 5.0, rand.Float64()*10.0 - 5.0}
+
+	settings := hego.CMAESSettings{}
+	settings.MaxIterations = 1000
+	settings.Verbose = settings.MaxIterations / 10
+	settings.Sigma = 2.0
+	settings.Restarts = 5
+	settings.Lower = []float64{-5.12, -5.12}
+	settings.Upper = []float64{5.12, 5.12}
+
+	result, err := hego.CMAES(rastringin, x0, settings)
+	if err != nil {
+		fmt.Printf("Got error while running CMA-ES: %v", err)
+	}
+	fmt.Printf("Finished CMA-ES after %v restarts! Result: %v, Value: %v \n", result.Restarts, result.BestCandidate, result.BestObjective)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ccssmnn/hego"
+)
+
+func rastringin(v []float64) float64 {
+	x, y := v[0], v[1]
+	return 10*2 + (x*x - 10*math.Cos(2*math.Pi*x)) + (y*y - 10*math.Cos(2*math.Pi*y))
+}
+
+func main() {
+
+	init := func() []float64 {
+		return []float64{rand.Float64()*10.0 - 5.0, rand.Float64()*10.0 - 5.0}
+	}
+
+	settings := hego.CuckooSettings{}
+	settings.MaxIterations = 500
+	settings.Verbose = settings.MaxIterations / 10
+	settings.Nests = 25
+	settings.DiscoveryRate = 0.25 // a quarter of the eggs is discovered
+	settings.StepSize = 0.01      // lévy flights are relative to the distance to the best nest
+	settings.Lower = []float64{-5.12, -5.12}
+	settings.Upper = []float64{5.12, 5.12}
+
+	result, err := hego.Cuckoo(rastringin, init, settings)
+	if err != nil {
+		fmt.Printf("Got error while running Cuckoo Search: %v", err)
+	}
+	fmt.Printf("Finished Cuckoo Search! Result: %v, Value: %v \n", result.BestCandidate, result.BestObjective)
+}
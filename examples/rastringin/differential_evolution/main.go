@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ccssmnn/hego"
+)
+
+func rastringin(v []float64) float64 {
+	x, y := v[0], v[1]
+	return 10*2 + (x*x - 10*math.Cos(2*math.Pi*x)) + (y*y - 10*math.Cos(2*math.Pi*y))
+}
+
+func main() {
+
+	init := func() []float64 {
+		return []float64{rand.Float64()*10.0 - 5.0, rand.Float64()*10.0 - 5.0}
+	}
+
+	settings := hego.DESettings{}
+	settings.MaxIterations = 200
+	settings.Verbose = settings.MaxIterations / 10
+	settings.PopulationSize = 30
+	settings.Adaptation = hego.SHADE
+	settings.Lower = []float64{-5.12, -5.12}
+	settings.Upper = []float64{5.12, 5.12}
+
+	result, err := hego.DE(rastringin, init, settings)
+	if err != nil {
+		fmt.Printf("Got error while running Differential Evolution: %v", err)
+	}
+	fmt.Printf("Finished Differential Evolution! Result: %v, Value: %v \n", result.BestCandidate, result.BestObjective)
+}
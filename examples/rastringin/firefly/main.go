@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ccssmnn/hego"
+)
+
+func rastringin(v []float64) float64 {
+	x, y := v[0], v[1]
+	return 10*2 + (x*x - 10*math.Cos(2*math.Pi*x)) + (y*y - 10*math.Cos(2*math.Pi*y))
+}
+
+func main() {
+
+	init := func() []float64 {
+		return []float64{rand.Float64()*10.0 - 5.0, rand.Float64()*10.0 - 5.0}
+	}
+
+	settings := hego.FireflySettings{}
+	settings.MaxIterations = 300
+	settings.Verbose = settings.MaxIterations / 10
+	settings.PopulationSize = 30
+	settings.Attraction = 0.2
+	settings.Absorption = 0.1     // fireflies see each other across the whole search space
+	settings.Randomization = 0.02 // random steps of 2% of the search space
+	settings.RandomizationDecay = 0.97
+	settings.Lower = []float64{-5.12, -5.12}
+	settings.Upper = []float64{5.12, 5.12}
+
+	result, err := hego.Firefly(rastringin, init, settings)
+	if err != nil {
+		fmt.Printf("Got error while running Firefly Algorithm: %v", err)
+	}
+	fmt.Printf("Finished Firefly Algorithm! Result: %v, Value: %v \n", result.BestCandidate, result.BestObjective)
+}
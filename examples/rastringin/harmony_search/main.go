@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ccssmnn/hego"
+)
+
+func rastringin(v []float64) float64 {
+	x, y := v[0], v[1]
+	return 10*2 + (x*x - 10*math.Cos(2*math.Pi*x)) + (y*y - 10*math.Cos(2*math.Pi*y))
+}
+
+func main() {
+
+	init := func() []float64 {
+		return []float64{rand.Float64()*10.0 - 5.0, rand.Float64()*10.0 - 5.0}
+	}
+
+	settings := hego.HSSettings{}
+	settings.MaxIterations = 20000
+	settings.Verbose = settings.MaxIterations / 10
+	settings.MemorySize = 20   // number of harmonies in memory
+	settings.MemoryRate = 0.95 // mostly improvise from memory
+	settings.PitchRate = 0.3
+	settings.Bandwidth = 0.01
+	settings.Lower = []float64{-5.12, -5.12}
+	settings.Upper = []float64{5.12, 5.12}
+
+	result, err := hego.HS(rastringin, init, settings)
+	if err != nil {
+		fmt.Printf("Got error while running Harmony Search: %v", err)
+	}
+	fmt.Printf("Finished Harmony Search! Result: %v, Value: %v \n", result.BestCandidate, result.BestObjective)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/ccssmnn/hego"
+	"github.com/ccssmnn/hego/mutate"
+)
+
+var distances = [48][48]float64{}
+
+func readDistances() error {
+	file, err := ioutil.ReadFile("../att48.txt")
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	lines := strings.Split(string(file), "\n")
+	if len(lines) != 48 {
+		return fmt.Errorf("file has wrong number of lines. Wanted 48, got %v", len(lines))
+	}
+	for row, line := range lines {
+		elems := strings.Split(line, " ")
+		col := 0
+		for _, elem := range elems {
+			if len(elem) > 0 {
+				distance, _ := strconv.Atoi(elem)
+				distances[row][col] = float64(distance)
+				col++
+			}
+		}
+	}
+	return nil
+}
+
+// state represents a tour of cities
+type state []int
+
+// Neighbor produces a similar tour by swapping two cities in a tour
+func (s state) Neighbor() hego.AnnealingState {
+	return state(mutate.Swap(s))
+}
+
+// Energy counts the total tour length
+func (s state) Energy() float64 {
+	cost := 0.0
+	position := s[0]
+	for _, next := range s {
+		cost += distances[position][next]
+		position = next
+	}
+	cost += distances[position][s[0]]
+	return cost
+}
+
+func main() {
+	// read problem file
+	err := readDistances()
+	if err != nil {
+		fmt.Printf("failed to read distances: %v", err)
+		return
+	}
+
+	// produce one initial tour
+	initialState := make(state, 48)
+	for i := range initialState {
+		initialState[i] = i
+	}
+	rand.Shuffle(len(initialState), func(i, j int) {
+		initialState[i], initialState[j] = initialState[j], initialState[i]
+	})
+
+	// the same state runs under all acceptance based methods
+	maxIterations := 1000000
+
+	saSettings := hego.SASettings{}
+	saSettings.MaxIterations = maxIterations
+	saSettings.Temperature = 10000.0     // choose temperature in the range of initial random guesses energies
+	saSettings.AnnealingFactor = 0.99999 // choose AnnealingFactor to make temperature reach low values at the end of the process
+	sa, err := hego.SA(initialState, saSettings)
+	if err != nil {
+		fmt.Printf("Got error while running SA: %v", err)
+	}
+	fmt.Printf("Finished Simulated Annealing in %v! Tour Length: %v \n", sa.Runtime, sa.BestEnergy)
+
+	gdSettings := hego.GDSettings{}
+	gdSettings.MaxIterations = maxIterations
+	gdSettings.Rain = 0.15 // lower the level from the initial tour length to good tour lengths during the process
+	gd, err := hego.GD(initialState, gdSettings)
+	if err != nil {
+		fmt.Printf("Got error while running GD: %v", err)
+	}
+	fmt.Printf("Finished Great Deluge in %v! Tour Length: %v \n", gd.Runtime, gd.BestEnergy)
+
+	rrtSettings := hego.RRTSettings{}
+	rrtSettings.MaxIterations = maxIterations
+	rrtSettings.Deviation = 1000.0 // allow tours slightly longer than the best tour
+	rrt, err := hego.RRT(initialState, rrtSettings)
+	if err != nil {
+		fmt.Printf("Got error while running RRT: %v", err)
+	}
+	fmt.Printf("Finished Record-to-Record Travel in %v! Tour Length: %v \n", rrt.Runtime, rrt.BestEnergy)
+
+	lahcSettings := hego.LAHCSettings{}
+	lahcSettings.MaxIterations = maxIterations
+	lahcSettings.HistoryLength = 1000 // compare candidates with the tour 1000 iterations ago
+	lahc, err := hego.LAHC(initialState, lahcSettings)
+	if err != nil {
+		fmt.Printf("Got error while running LAHC: %v", err)
+	}
+	fmt.Printf("Finished Late Acceptance Hill Climbing in %v! Tour Length: %v \n", lahc.Runtime, lahc.BestEnergy)
+}
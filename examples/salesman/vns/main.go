@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/ccssmnn/hego"
+	"github.com/ccssmnn/hego/mutate"
+)
+
+var distances = [48][48]float64{}
+
+func readDistances() error {
+	file, err := ioutil.ReadFile("../att48.txt")
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	lines := strings.Split(string(file), "\n")
+	if len(lines) != 48 {
+		return fmt.Errorf("file has wrong number of lines. Wanted 48, got %v", len(lines))
+	}
+	for row, line := range lines {
+		elems := strings.Split(line, " ")
+		col := 0
+		for _, elem := range elems {
+			if len(elem) > 0 {
+				distance, _ := strconv.Atoi(elem)
+				distances[row][col] = float64(distance)
+				col++
+			}
+		}
+	}
+	return nil
+}
+
+// state represents a tour of cities
+type state []int
+
+// Neighborhoods returns the number of neighborhood structures
+func (s state) Neighborhoods() int {
+	return 3
+}
+
+// Neighbor produces a similar tour. The first neighborhood swaps two
+// neighboring cities, the second two arbitrary cities and the third
+// performs three random swaps
+func (s state) Neighbor(k int) hego.VNSState {
+	switch k {
+	case 0:
+		return state(mutate.SwapClose(s))
+	case 1:
+		return state(mutate.Swap(s))
+	default:
+		next := []int(s)
+		for i := 0; i < 3; i++ {
+			next = mutate.Swap(next)
+		}
+		return state(next)
+	}
+}
+
+// Objective counts the total tour length
+func (s state) Objective() float64 {
+	cost := 0.0
+	position := s[0]
+	for _, next := range s {
+		cost += distances[position][next]
+		position = next
+	}
+	cost += distances[position][s[0]]
+	return cost
+}
+
+func main() {
+	// read problem file
+	err := readDistances()
+	if err != nil {
+		fmt.Printf("failed to read distances: %v", err)
+		return
+	}
+
+	// produce one initial tour
+	initialState := make(state, 48)
+	for i := range initialState {
+		initialState[i] = i
+	}
+	rand.Shuffle(len(initialState), func(i, j int) {
+		initialState[i], initialState[j] = initialState[j], initialState[i]
+	})
+
+	// set algorithm parameters
+	settings := hego.VNSSettings{}
+	settings.MaxIterations = 1000
+	settings.Verbose = settings.MaxIterations / 10 // log 10 times during the process
+	settings.LocalSearchSize = 500
+	settings.VariableDescent = true
+
+	// start Variable Neighborhood Search
+	result, err := hego.VNS(initialState, settings)
+
+	if err != nil {
+		fmt.Printf("Got error while running Variable Neighborhood Search: %v", err)
+	}
+
+	fmt.Printf("Finished Variable Neighborhood Search in %v! Tour Length: %v \n", result.Runtime, result.BestObjective)
+}
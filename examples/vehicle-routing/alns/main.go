@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ccssmnn/hego"
+)
+
+var nCities int
+var depot int
+var nVehicles int
+
+var distances = [17][17]float64{
+	{0, 548, 776, 696, 582, 274, 502, 194, 308, 194, 536, 502, 388, 354, 468, 776, 662},
+	{548, 0, 684, 308, 194, 502, 730, 354, 696, 742, 1084, 594, 480, 674, 1016, 868, 1210},
+	{776, 684, 0, 992, 878, 502, 274, 810, 468, 742, 400, 1278, 1164, 1130, 788, 1552, 754},
+	{696, 308, 992, 0, 114, 650, 878, 502, 844, 890, 1232, 514, 628, 822, 1164, 560, 1358},
+	{582, 194, 878, 114, 0, 536, 764, 388, 730, 776, 1118, 400, 514, 708, 1050, 674, 1244},
+	{274, 502, 502, 650, 536, 0, 228, 308, 194, 240, 582, 776, 662, 628, 514, 1050, 708},
+	{502, 730, 274, 878, 764, 228, 0, 536, 194, 468, 354, 1004, 890, 856, 514, 1278, 480},
+	{194, 354, 810, 502, 388, 308, 536, 0, 342, 388, 730, 468, 354, 320, 662, 742, 856},
+	{308, 696, 468, 844, 730, 194, 194, 342, 0, 274, 388, 810, 696, 662, 320, 1084, 514},
+	{194, 742, 742, 890, 776, 240, 468, 388, 274, 0, 342, 536, 422, 388, 274, 810, 468},
+	{536, 1084, 400, 1232, 1118, 582, 354, 730, 388, 342, 0, 878, 764, 730, 388, 1152, 354},
+	{502, 594, 1278, 514, 400, 776, 1004, 468, 810, 536, 878, 0, 114, 308, 650, 274, 844},
+	{388, 480, 1164, 628, 514, 662, 890, 354, 696, 422, 764, 114, 0, 194, 536, 388, 730},
+	{354, 674, 1130, 822, 708, 628, 856, 320, 662, 388, 730, 308, 194, 0, 342, 422, 536},
+	{468, 1016, 788, 1164, 1050, 514, 514, 662, 320, 274, 388, 650, 536, 342, 0, 764, 194},
+	{776, 868, 1552, 560, 674, 1050, 1278, 742, 1084, 810, 1152, 274, 388, 422, 764, 0, 798},
+	{662, 1210, 754, 1358, 1244, 708, 480, 856, 514, 468, 354, 844, 730, 536, 194, 798, 0},
+}
+
+// state holds one tour per vehicle. Tours start and end at the depot, which is
+// not part of the tour. removed holds the cities taken out by a destroy operator
+type state struct {
+	tours   [][]int
+	removed []int
+}
+
+// clone returns a deep copy of the tours
+func (s state) clone() state {
+	tours := make([][]int, len(s.tours))
+	for v, tour := range s.tours {
+		tours[v] = append([]int{}, tour...)
+	}
+	return state{tours: tours, removed: append([]int{}, s.removed...)}
+}
+
+// remove takes the city at position i of tour v out of the solution
+func (s *state) remove(v, i int) {
+	s.removed = append(s.removed, s.tours[v][i])
+	s.tours[v] = append(s.tours[v][:i], s.tours[v][i+1:]...)
+}
+
+// insertionCost is the additional length of inserting city at position i of tour
+func insertionCost(tour []int, i, city int) float64 {
+	prev, next := depot, depot
+	if i > 0 {
+		prev = tour[i-1]
+	}
+	if i < len(tour) {
+		next = tour[i]
+	}
+	return distances[prev][city] + distances[city][next] - distances[prev][next]
+}
+
+// Objective is the total tour length
+func (s state) Objective() float64 {
+	totalLength := 0.0
+	for _, tour := range s.tours {
+		position := depot
+		for _, next := range tour {
+			totalLength += distances[position][next]
+			position = next
+		}
+		totalLength += distances[position][depot]
+	}
+	return totalLength
+}
+
+// randomRemoval removes 2 to 5 random cities
+func randomRemoval(s hego.ALNSState) hego.ALNSState {
+	next := s.(state).clone()
+	for k := 2 + rand.Intn(4); k > 0; k-- {
+		v := rand.Intn(nVehicles)
+		if len(next.tours[v]) > 0 {
+			next.remove(v, rand.Intn(len(next.tours[v])))
+		}
+	}
+	return next
+}
+
+// worstRemoval removes the 2 to 5 cities that cause the longest detours
+func worstRemoval(s hego.ALNSState) hego.ALNSState {
+	next := s.(state).clone()
+	for k := 2 + rand.Intn(4); k > 0; k-- {
+		worstV, worstI, worstCost := -1, -1, math.Inf(-1)
+		for v, tour := range next.tours {
+			for i := range tour {
+				rest := append(append([]int{}, tour[:i]...), tour[i+1:]...)
+				if cost := insertionCost(rest, i, tour[i]); cost > worstCost {
+					worstV, worstI, worstCost = v, i, cost
+				}
+			}
+		}
+		if worstV >= 0 {
+			next.remove(worstV, worstI)
+		}
+	}
+	return next
+}
+
+// greedyInsertion inserts the removed cities one after another at their cheapest position
+func greedyInsertion(s hego.ALNSState) hego.ALNSState {
+	next := s.(state).clone()
+	for _, city := range next.removed {
+		bestV, bestI, bestCost := 0, 0, math.MaxFloat64
+		for v, tour := range next.tours {
+			for i := 0; i <= len(tour); i++ {
+				if cost := insertionCost(tour, i, city); cost < bestCost {
+					bestV, bestI, bestCost = v, i, cost
+				}
+			}
+		}
+		tour := next.tours[bestV]
+		next.tours[bestV] = append(tour[:bestI], append([]int{city}, tour[bestI:]...)...)
+	}
+	next.removed = nil
+	return next
+}
+
+// randomInsertion inserts the removed cities at the cheapest position of a random tour
+func randomInsertion(s hego.ALNSState) hego.ALNSState {
+	next := s.(state).clone()
+	for _, city := range next.removed {
+		v := rand.Intn(nVehicles)
+		tour := next.tours[v]
+		bestI, bestCost := 0, math.MaxFloat64
+		for i := 0; i <= len(tour); i++ {
+			if cost := insertionCost(tour, i, city); cost < bestCost {
+				bestI, bestCost = i, cost
+			}
+		}
+		next.tours[v] = append(tour[:bestI], append([]int{city}, tour[bestI:]...)...)
+	}
+	next.removed = nil
+	return next
+}
+
+func main() {
+	nCities = 17
+	depot = 0
+	nVehicles = 4
+
+	// randomly assign every city but the depot to a vehicle
+	initialState := state{tours: make([][]int, nVehicles)}
+	for _, city := range rand.Perm(nCities) {
+		if city != depot {
+			v := rand.Intn(nVehicles)
+			initialState.tours[v] = append(initialState.tours[v], city)
+		}
+	}
+
+	destroy := []hego.DestroyOperator{randomRemoval, worstRemoval}
+	repair := []hego.RepairOperator{greedyInsertion, randomInsertion}
+
+	// set algorithm parameters
+	settings := hego.ALNSSettings{}
+	settings.MaxIterations = 10000
+	settings.Verbose = settings.MaxIterations / 10 // log 10 times during the process
+	settings.ScoreBest = 10.0                      // reward operators finding a new best solution the most
+	settings.ScoreBetter = 5.0
+	settings.ScoreAccepted = 1.0
+	settings.ReactionFactor = 0.1 // slowly adapt the operator weights
+	settings.SegmentLength = 100
+	settings.Acceptance = &hego.AnnealingAcceptance{
+		Temperature:     100.0,  // accept detours of a few hundred units in the beginning
+		AnnealingFactor: 0.9995, // cool down to low temperatures at the end of the process
+	}
+
+	// start adaptive large neighborhood search
+	result, err := hego.ALNS(initialState, destroy, repair, settings)
+
+	if err != nil {
+		fmt.Printf("Got error while running ALNS: %v", err)
+	}
+	fmt.Printf("Finished Adaptive Large Neighborhood Search in %v! Tour Length: %v \n", result.Runtime, result.BestObjective)
+	fmt.Printf("Destroy operator weights: %v, repair operator weights: %v\n", result.DestroyWeights, result.RepairWeights)
+	for v, tour := range result.BestState.(state).tours {
+		fmt.Printf("Vehicle %v: %v\n", v, tour)
+	}
+}
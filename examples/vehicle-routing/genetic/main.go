@@ -82,8 +82,13 @@ func (g genome) Crossover(other hego.Genome) hego.Genome {
 	return other
 }
 
-// Mutate changes the assignment of a city or the order of a city in a tour
+// Mutate changes the assignment of a city or the order of a city in a tour of
+// a copy of the genome
 func (g genome) Mutate() hego.Genome {
+	g = genome{
+		assignment: append([]int{}, g.assignment...),
+		order:      append([]int{}, g.order...),
+	}
 	if rand.Float64() > 0.5 {
 		// change assignment
 		i, j := rand.Intn(nCities), rand.Intn(nVehicles)
@@ -0,0 +1,180 @@
+package hego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// FireflyResult represents the result of the firefly algorithm
+type FireflyResult struct {
+	BestCandidates    [][]float64
+	BestObjectives    []float64
+	AverageObjectives []float64
+	BestCandidate     []float64
+	BestObjective     float64
+	Result
+}
+
+// FireflySettings represents settings for the firefly algorithm
+type FireflySettings struct {
+	// PopulationSize is the number of fireflies, at least 2
+	PopulationSize int
+	// Attraction is the attractiveness of a brighter firefly at distance 0
+	Attraction float64
+	// Absorption is the light absorption coefficient. The attractiveness decays
+	// with exp(-Absorption * r^2) over the distance r. 0 lets every firefly
+	// attract all others equally
+	Absorption float64
+	// Randomization is the size of the random step of every move. With bounds,
+	// it is relative to the width of each dimension
+	Randomization float64
+	// RandomizationDecay in (0, 1] is multiplied to Randomization after every
+	// iteration. When 0, the randomization is constant
+	RandomizationDecay float64
+	Bounds
+	Settings
+}
+
+// Verify checks the validity of the settings and returns nil if everything is ok
+func (s *FireflySettings) Verify() error {
+	if s.PopulationSize < 2 {
+		return fmt.Errorf("population size must be at least 2, got %v", s.PopulationSize)
+	}
+	if s.Attraction <= 0.0 {
+		return fmt.Errorf("attraction must be greater than 0, got %v", s.Attraction)
+	}
+	if s.Absorption < 0.0 {
+		return fmt.Errorf("absorption must not be negative, got %v", s.Absorption)
+	}
+	if s.Randomization < 0.0 {
+		return fmt.Errorf("randomization must not be negative, got %v", s.Randomization)
+	}
+	if s.RandomizationDecay < 0.0 || s.RandomizationDecay > 1.0 {
+		return fmt.Errorf("randomization decay must be in (0, 1], got %v", s.RandomizationDecay)
+	}
+	return s.Bounds.Verify()
+}
+
+// Firefly performs the Firefly Algorithm (Yang) for minimizing a real valued
+// function (objective). init is called to create every firefly of the initial
+// population. A firefly is brighter the lower its objective value. Every
+// iteration each firefly moves towards all brighter fireflies, attracted less
+// with increasing distance, plus a random step. The brightest firefly only
+// moves randomly
+func Firefly(
+	objective func(x []float64) float64,
+	init func() []float64,
+	settings FireflySettings) (res FireflyResult, err error) {
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return res, err
+	}
+	start := time.Now()
+	logger := newLogger("Firefly Algorithm", []string{"Iteration", "Population Mean", "Population Best"}, settings.Verbose, settings.MaxIterations)
+	// increase funcEvaluations counter for every call to objective
+	evaluate := func(x []float64) float64 {
+		res.FuncEvaluations++
+		return objective(x) + settings.penalty(x)
+	}
+	if settings.KeepHistory {
+		res.BestCandidates = make([][]float64, 0, settings.MaxIterations)
+		res.BestObjectives = make([]float64, 0, settings.MaxIterations)
+		res.AverageObjectives = make([]float64, 0, settings.MaxIterations)
+	}
+	decay := settings.RandomizationDecay
+	if decay == 0.0 {
+		decay = 1.0
+	}
+
+	fireflies := make([][]float64, settings.PopulationSize)
+	objs := make([]float64, settings.PopulationSize)
+	best := 0
+	for i := range fireflies {
+		fireflies[i] = init()
+		if err = settings.verifyDimension(len(fireflies[i])); err != nil {
+			return res, err
+		}
+		settings.repair(fireflies[i], nil)
+		objs[i] = evaluate(fireflies[i])
+		if objs[i] < objs[best] {
+			best = i
+		}
+	}
+
+	n := len(fireflies[0])
+	// the random step is scaled to the width of the bounds
+	scale := make([]float64, n)
+	for d := range scale {
+		scale[d] = 1.0
+		if settings.bounded() {
+			scale[d] = settings.Upper[d] - settings.Lower[d]
+		}
+	}
+	// the best candidate is tracked separately, because the brightest firefly
+	// may move to a worse position
+	res.BestObjective = objs[best]
+	res.BestCandidate = make([]float64, n)
+	copy(res.BestCandidate, fireflies[best])
+	randomization := settings.Randomization
+	for i := 0; i < settings.MaxIterations; i++ {
+		// fireflies move towards the positions of the previous iteration
+		previous := make([][]float64, settings.PopulationSize)
+		for j := range fireflies {
+			previous[j] = make([]float64, n)
+			copy(previous[j], fireflies[j])
+		}
+		totalObj := 0.0
+		for j, x := range fireflies {
+			for k, y := range previous {
+				if objs[k] >= objs[j] {
+					continue
+				}
+				r2 := 0.0
+				for d := range x {
+					r2 += (y[d] - x[d]) * (y[d] - x[d])
+				}
+				attraction := settings.Attraction * math.Exp(-settings.Absorption*r2)
+				for d := range x {
+					x[d] += attraction * (y[d] - x[d])
+				}
+			}
+			for d := range x {
+				x[d] += randomization * (rand.Float64() - 0.5) * scale[d]
+			}
+			settings.repair(x, nil)
+		}
+		for j, x := range fireflies {
+			objs[j] = evaluate(x)
+			totalObj += objs[j]
+			if objs[j] < res.BestObjective {
+				res.BestObjective = objs[j]
+				copy(res.BestCandidate, x)
+			}
+		}
+		randomization *= decay
+
+		if settings.KeepHistory {
+			candidate := make([]float64, n)
+			copy(candidate, res.BestCandidate)
+			res.BestCandidates = append(res.BestCandidates, candidate)
+			res.BestObjectives = append(res.BestObjectives, res.BestObjective)
+			res.AverageObjectives = append(res.AverageObjectives, totalObj/float64(settings.PopulationSize))
+		}
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(totalObj / float64(settings.PopulationSize)),
+			fmt.Sprint(res.BestObjective),
+		})
+	}
+
+	res.Runtime = time.Since(start)
+	res.Iterations = settings.MaxIterations
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return res, nil
+}
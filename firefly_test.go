@@ -0,0 +1,73 @@
+package hego
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestVerifyFireflySettings(t *testing.T) {
+	settings := FireflySettings{PopulationSize: 1}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with population size 1")
+	}
+	settings.PopulationSize = 20
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with attraction 0")
+	}
+	settings.Attraction = 1.0
+	settings.Absorption = -1.0
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with negative absorption")
+	}
+	settings.Absorption = 1.0
+	settings.RandomizationDecay = 1.5
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with randomization decay > 1")
+	}
+	settings.RandomizationDecay = 0.0
+	if err := settings.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+}
+
+func TestFirefly(t *testing.T) {
+	sphere := func(x []float64) float64 {
+		return x[0]*x[0] + x[1]*x[1] + x[2]*x[2]
+	}
+	init := func() []float64 {
+		x := make([]float64, 3)
+		for i := range x {
+			x[i] = -5.12 + 10.24*rand.Float64()
+		}
+		return x
+	}
+	settings := FireflySettings{}
+	_, err := Firefly(sphere, init, settings)
+	if err == nil {
+		t.Error("Firefly should fail with invalid settings")
+	}
+	settings.PopulationSize = 25
+	settings.Attraction = 1.0
+	settings.Absorption = 1.0
+	settings.Randomization = 0.2
+	settings.RandomizationDecay = 0.97
+	settings.MaxIterations = 200
+	settings.KeepHistory = true
+	settings.Lower = []float64{-5.12, -5.12, -5.12}
+	settings.Upper = []float64{5.12, 5.12, 5.12}
+	res, err := Firefly(sphere, init, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in Firefly: %v", err)
+	}
+	if len(res.AverageObjectives) != settings.MaxIterations {
+		t.Errorf("expected history for every iteration, got %v", len(res.AverageObjectives))
+	}
+	if res.BestObjective > 1e-4 {
+		t.Errorf("Firefly produced unexpected result, got %v", res.BestObjective)
+	}
+	for i := 1; i < len(res.BestObjectives); i++ {
+		if res.BestObjectives[i] > res.BestObjectives[i-1] {
+			t.Fatal("best objective must not increase")
+		}
+	}
+}
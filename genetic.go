@@ -77,7 +77,10 @@ func binaryWeightedChoice(weights []float64, n int) []int {
 	return choices
 }
 
-// Genome represents a genome (candidate) in the genetic algorithm
+// Genome represents a genome (candidate) in the genetic algorithm. The same
+// genome can appear several times in a population and, with IslandGA, on
+// several islands at once. Mutate and Crossover must therefore return new
+// genomes and never modify the receiver or other
 type Genome interface {
 	// Fitness returns the objective function value for this genome
 	Fitness() float64
@@ -92,8 +95,20 @@ type GAResult struct {
 	AveragedFitnesses []float64
 	BestFitnesses     []float64
 	BestGenomes       []Genome
-	BestGenome        Genome
-	BestFitness       float64
+	// Diversities holds the mean pairwise distance of the population for
+	// each iteration. Only recorded when genomes implement Distancer
+	Diversities []float64
+	// MutationRates holds the mutation rate for each iteration. With
+	// SelfAdaptation it is the mean rate of the population
+	MutationRates []float64
+	// CrossoverRates holds the crossover rate for each iteration. With
+	// SelfAdaptation it is the mean rate of the population
+	CrossoverRates []float64
+	BestGenome     Genome
+	BestFitness    float64
+	// LocalSearchEvaluations counts the fitness evaluations of genomes produced
+	// by local search. These are not included in FuncEvaluations
+	LocalSearchEvaluations int
 	Result
 }
 
@@ -119,8 +134,43 @@ type GASettings struct {
 	TournamentSize int
 	// MutationRate is the probability of a candidate to mutate after crossover
 	MutationRate float64
+	// CrossoverRate is the probability of two parents to be recombined, otherwise
+	// the offspring is a copy of the first parent. 0 is treated as 1.0, parents
+	// are always recombined
+	CrossoverRate float64
+	// Adaptation defines how MutationRate and CrossoverRate are controlled during
+	// the run. The rates from the settings are used as initial values
+	Adaptation RateAdaptation
+	// AdaptationFactor controls the speed of rate adaptation. For
+	// OneFifthAdaptation and DiversityAdaptation the rates are multiplied or
+	// divided by this factor, which must be in (0, 1). For SelfAdaptation it is
+	// the learning rate of the log-normal rate mutation and must be above 0
+	AdaptationFactor float64
+	// TargetDiversity is the population diversity aimed for by
+	// DiversityAdaptation. Requires genomes implementing Distancer
+	TargetDiversity float64
+	// LocalSearchRate is the fraction of offspring improved by local search
+	// (memetic algorithm). Requires genomes implementing LocalSearcher
+	LocalSearchRate float64
+	// LocalSearchMode defines how the result of the local search is used
+	LocalSearchMode LocalSearchMode
 	// Elitism is the number of best candidates to pass over to the next generation without selection
 	Elitism int
+	// CacheSize is the number of fitness values kept in a least recently used
+	// cache. It is only used for genomes implementing Keyed. 0 disables the cache
+	CacheSize int
+	// SharingRadius enables fitness sharing when greater than 0. Genomes closer
+	// than SharingRadius share their fitness, which makes crowded niches less
+	// attractive during selection. Requires genomes implementing Distancer
+	SharingRadius float64
+	// Crowding enables deterministic crowding replacement instead of selection.
+	// Random pairs of genomes produce two offspring, each offspring competes
+	// with the more similar parent and only the better one keeps the parent's
+	// slot. Requires genomes implementing Distancer
+	Crowding bool
+	// EliminateDuplicates mutates offspring that are identical (distance 0) to a
+	// genome of the next generation. Requires genomes implementing Distancer
+	EliminateDuplicates bool
 	Settings
 }
 
@@ -132,15 +182,73 @@ func (s *GASettings) Verify() error {
 	if s.Elitism < 0 {
 		return errors.New("elitism cannot be negative")
 	}
+	if s.CacheSize < 0 {
+		return errors.New("cache size cannot be negative")
+	}
 	if s.Selection == TournamentSelection && s.TournamentSize < 2 {
 		return errors.New("when TournamentSelection is set, TournamentSize must be a value above 1")
 	}
+	if s.SharingRadius < 0.0 {
+		return fmt.Errorf("sharing radius cannot be negative, got %v", s.SharingRadius)
+	}
+	if s.CrossoverRate > 1.0 || s.CrossoverRate < 0.0 {
+		return fmt.Errorf("crossover rate must be between 0.0 and 1.0, not %v", s.CrossoverRate)
+	}
+	switch s.Adaptation {
+	case FixedRates:
+	case OneFifthAdaptation, DiversityAdaptation:
+		if s.AdaptationFactor <= 0.0 || s.AdaptationFactor >= 1.0 {
+			return fmt.Errorf("adaptation factor must be between 0.0 and 1.0, got %v", s.AdaptationFactor)
+		}
+		if s.Adaptation == DiversityAdaptation && s.TargetDiversity <= 0.0 {
+			return fmt.Errorf("target diversity must be greater than 0.0, got %v", s.TargetDiversity)
+		}
+	case SelfAdaptation:
+		if s.AdaptationFactor <= 0.0 {
+			return fmt.Errorf("adaptation factor must be greater than 0.0, got %v", s.AdaptationFactor)
+		}
+	default:
+		return fmt.Errorf("unknown rate adaptation %v", s.Adaptation)
+	}
+	if s.LocalSearchRate > 1.0 || s.LocalSearchRate < 0.0 {
+		return fmt.Errorf("local search rate must be between 0.0 and 1.0, not %v", s.LocalSearchRate)
+	}
+	if s.LocalSearchMode != LamarckianLocalSearch && s.LocalSearchMode != BaldwinianLocalSearch {
+		return fmt.Errorf("unknown local search mode %v", s.LocalSearchMode)
+	}
+	return nil
+}
+
+// verifyGenomes returns an error, if the genomes do not support the settings
+func (s *GASettings) verifyGenomes(genomes []Genome) error {
+	if len(genomes) == 0 {
+		return errors.New("population must not be empty")
+	}
+	if s.SharingRadius > 0.0 || s.Crowding || s.EliminateDuplicates || s.Adaptation == DiversityAdaptation {
+		if _, ok := genomes[0].(Distancer); !ok {
+			return errors.New("fitness sharing, crowding, duplicate elimination and diversity adaptation require genomes implementing Distancer")
+		}
+	}
+	if s.LocalSearchRate > 0.0 {
+		if _, ok := genomes[0].(LocalSearcher); !ok {
+			return errors.New("local search requires genomes implementing LocalSearcher")
+		}
+	}
 	return nil
 }
 
 type candidate struct {
 	genome  Genome
 	fitness float64
+	// raw is the fitness before sharing, only set in shared populations
+	raw float64
+	// genomeFitness is the fitness of genome. It differs from fitness after
+	// Baldwinian local search and is the fitness reported in the result
+	genomeFitness float64
+	// mutationRate and crossoverRate are the rates carried by the genome
+	// with SelfAdaptation
+	mutationRate  float64
+	crossoverRate float64
 }
 
 type population []candidate
@@ -236,6 +344,210 @@ func (p population) selectParents(settings *GASettings) []int {
 	return parentIds
 }
 
+// island holds one evolving GA population together with its statistics. GA
+// evolves a single island, IslandGA evolves several of them in parallel
+type island struct {
+	pop      population
+	settings *GASettings
+	cache    *evalCache
+	// distancer is true when genomes implement Distancer
+	distancer bool
+	// mutationRate and crossoverRate are the current rates of the island
+	mutationRate  float64
+	crossoverRate float64
+	res           GAResult
+}
+
+// newIsland evaluates the initial genomes and prepares the history
+func newIsland(genomes []Genome, settings *GASettings) *island {
+	is := island{settings: settings, cache: newEvalCache(settings.CacheSize)}
+	is.mutationRate = settings.MutationRate
+	is.crossoverRate = settings.CrossoverRate
+	if is.crossoverRate == 0.0 {
+		is.crossoverRate = 1.0
+	}
+	is.pop = make(population, len(genomes))
+	for i := range genomes {
+		is.pop[i].genome = genomes[i]
+		is.pop[i].fitness = is.evaluate(genomes[i])
+		is.pop[i].genomeFitness = is.pop[i].fitness
+		is.pop[i].mutationRate = is.mutationRate
+		is.pop[i].crossoverRate = is.crossoverRate
+	}
+	_, is.distancer = genomes[0].(Distancer)
+	if settings.KeepHistory {
+		is.res.AveragedFitnesses = make([]float64, settings.MaxIterations)
+		is.res.BestFitnesses = make([]float64, settings.MaxIterations)
+		is.res.BestGenomes = make([]Genome, settings.MaxIterations)
+		if is.distancer {
+			is.res.Diversities = make([]float64, settings.MaxIterations)
+		}
+		is.res.MutationRates = make([]float64, settings.MaxIterations)
+		is.res.CrossoverRates = make([]float64, settings.MaxIterations)
+	}
+	is.res.BestFitness = math.MaxFloat64
+	return &is
+}
+
+// evaluate increases FuncEvaluations for every fitness call and CacheHits
+// for every fitness value found in the cache
+func (is *island) evaluate(g Genome) float64 {
+	if fitness, ok := is.cache.lookup(g); ok {
+		is.res.CacheHits++
+		return fitness
+	}
+	is.res.FuncEvaluations++
+	fitness := g.Fitness()
+	is.cache.store(g, fitness)
+	return fitness
+}
+
+// record updates history and best genome for iteration i and returns the
+// average and best fitness of the current population. It uses the fitness of
+// the genomes, not the fitness assigned by Baldwinian local search
+func (is *island) record(i int) (averageFitness, bestFitness float64) {
+	totalFitness := 0.0
+	bestFitness = math.MaxFloat64
+	bestIndex := -1
+	for idx, g := range is.pop {
+		totalFitness += g.genomeFitness
+		if g.genomeFitness < bestFitness {
+			bestFitness = g.genomeFitness
+			bestIndex = idx
+		}
+	}
+	averageFitness = totalFitness / float64(len(is.pop))
+
+	if is.settings.KeepHistory {
+		is.res.AveragedFitnesses[i] = averageFitness
+		is.res.BestFitnesses[i] = bestFitness
+		is.res.BestGenomes[i] = is.pop[bestIndex].genome
+		if is.distancer {
+			is.res.Diversities[i] = is.pop.diversity()
+		}
+		is.res.MutationRates[i] = is.mutationRate
+		is.res.CrossoverRates[i] = is.crossoverRate
+	}
+
+	if is.res.BestFitness > bestFitness {
+		is.res.BestFitness = bestFitness
+		is.res.BestGenome = is.pop[bestIndex].genome
+	}
+	return
+}
+
+// evolve produces the next generation by selection, crossover and mutation
+func (is *island) evolve() {
+	settings := is.settings
+	pop := is.pop
+
+	// TODO: for elitism << len(pop) it is more efficient to extract smallest n instead of sorting
+	if settings.Elitism > 0 {
+		sort.Sort(&pop)
+	}
+	if settings.Crowding {
+		successes, n := is.crowding()
+		is.adaptRates(successes, n)
+		is.res.Iterations++
+		return
+	}
+
+	// SELECTION
+	parents := pop
+	if settings.SharingRadius > 0.0 {
+		parents = pop.shared(settings.SharingRadius)
+	}
+	parentIds := parents.selectParents(settings)
+
+	// CROSSOVER & MUTATION
+	successes := 0
+	for idx := settings.Elitism; idx < len(pop); idx++ {
+		parent1 := parents[parentIds[rand.Intn(len(parentIds))]]
+		parent2 := parents[parentIds[rand.Intn(len(parentIds))]]
+		if settings.SharingRadius > 0.0 {
+			parent1.fitness, parent2.fitness = parent1.raw, parent2.raw
+		}
+		offspring := is.breed(parent1, parent2, pop[:idx])
+		if offspring.fitness < math.Min(parent1.fitness, parent2.fitness) {
+			successes++
+		}
+		pop[idx] = offspring
+	}
+	is.adaptRates(successes, len(pop)-settings.Elitism)
+	is.res.Iterations++
+}
+
+// breed produces an evaluated offspring of parent1 and parent2 by crossover,
+// mutation and local search. With EliminateDuplicates, offspring identical to
+// a genome of next are mutated again
+func (is *island) breed(parent1, parent2 candidate, next population) candidate {
+	settings := is.settings
+	offspring := candidate{mutationRate: is.mutationRate, crossoverRate: is.crossoverRate}
+	if settings.Adaptation == SelfAdaptation {
+		offspring.mutationRate, offspring.crossoverRate = selfAdaptedRates(parent1, parent2, settings.AdaptationFactor)
+	}
+	if rand.Float64() < offspring.crossoverRate {
+		offspring.genome = parent1.genome.Crossover(parent2.genome)
+	} else {
+		offspring.genome = parent1.genome
+	}
+	if rand.Float64() < offspring.mutationRate {
+		offspring.genome = offspring.genome.Mutate()
+	}
+	if settings.EliminateDuplicates {
+		for retries := 0; retries < maxDuplicateRetries && next.contains(offspring.genome); retries++ {
+			offspring.genome = offspring.genome.Mutate()
+		}
+	}
+	offspring.fitness = is.evaluate(offspring.genome)
+	offspring.genomeFitness = offspring.fitness
+	if settings.LocalSearchRate > 0.0 && rand.Float64() < settings.LocalSearchRate {
+		is.improve(&offspring)
+	}
+	return offspring
+}
+
+// crowding performs deterministic crowding (Mahfoud). The genomes after the
+// elites are paired at random and every pair produces two offspring. Each
+// offspring competes against the more similar parent of the pairing that
+// minimizes the total distance and replaces it in its slot if it is not worse.
+// It returns the number of offspring better than both parents and the number
+// of offspring
+func (is *island) crowding() (successes, n int) {
+	pop := is.pop
+	elitism := is.settings.Elitism
+	slots := rand.Perm(len(pop) - elitism)
+	for k := 0; k+1 < len(slots); k += 2 {
+		i, j := elitism+slots[k], elitism+slots[k+1]
+		parent1, parent2 := pop[i], pop[j]
+		offspring1 := is.breed(parent1, parent2, pop)
+		next := pop
+		if is.settings.EliminateDuplicates {
+			next = append(population{offspring1}, pop...)
+		}
+		offspring2 := is.breed(parent2, parent1, next)
+
+		d1, d2 := offspring1.genome.(Distancer), offspring2.genome.(Distancer)
+		if d1.Distance(parent2.genome)+d2.Distance(parent1.genome) < d1.Distance(parent1.genome)+d2.Distance(parent2.genome) {
+			offspring1, offspring2 = offspring2, offspring1
+		}
+		best := math.Min(parent1.fitness, parent2.fitness)
+		for _, contest := range []struct {
+			slot      int
+			offspring candidate
+		}{{i, offspring1}, {j, offspring2}} {
+			if contest.offspring.fitness < best {
+				successes++
+			}
+			if contest.offspring.fitness <= pop[contest.slot].fitness {
+				pop[contest.slot] = contest.offspring
+			}
+		}
+		n += 2
+	}
+	return successes, n
+}
+
 // GA Performs optimization. The optimization follows three steps:
 // - for current population calculate fitness
 // - select chromosomes with best fitness values with higher propability as parents
@@ -249,80 +561,30 @@ func GA(
 		err = fmt.Errorf("settings verification failed: %v", err)
 		return
 	}
-	// increase FuncEvaluations for every fitness call
-	evaluate := func(g Genome) float64 {
-		res.FuncEvaluations++
-		return g.Fitness()
+	err = settings.verifyGenomes(initialPopulation)
+	if err != nil {
+		return
 	}
 
 	start := time.Now()
 	logger := newLogger("Genetic Algorithm", []string{"Iteration", "Average Fitness", "Best Fitness"}, settings.Verbose, settings.MaxIterations)
 
-	pop := make(population, len(initialPopulation))
-	for i := range initialPopulation {
-		pop[i].genome = initialPopulation[i]
-		pop[i].fitness = evaluate(pop[i].genome)
-	}
-
-	if settings.KeepHistory {
-		res.AveragedFitnesses = make([]float64, settings.MaxIterations)
-		res.BestFitnesses = make([]float64, settings.MaxIterations)
-		res.BestGenomes = make([]Genome, settings.MaxIterations)
-	}
-
-	res.BestFitness = math.MaxFloat64
+	is := newIsland(initialPopulation, &settings)
 
 	for i := 0; i < settings.MaxIterations; i++ {
 		// FITNESS EVALUATION
-		totalFitness := 0.0
-		bestFitness := math.MaxFloat64
-		bestIndex := -1
-		for idx, g := range pop {
-			totalFitness += g.fitness
-			if g.fitness < bestFitness {
-				bestFitness = g.fitness
-				bestIndex = idx
-			}
-		}
-
-		if settings.KeepHistory {
-			res.AveragedFitnesses[i] = totalFitness / float64(len(pop))
-			res.BestFitnesses[i] = bestFitness
-			res.BestGenomes[i] = pop[bestIndex].genome
-		}
-
-		if res.BestFitness > bestFitness {
-			res.BestFitness = bestFitness
-			res.BestGenome = pop[bestIndex].genome
-		}
+		averageFitness, bestFitness := is.record(i)
 
 		logger.AddLine(i, []string{
 			fmt.Sprint(i),
-			fmt.Sprint(totalFitness / float64(len(pop))),
+			fmt.Sprint(averageFitness),
 			fmt.Sprint(bestFitness),
 		})
 
-		// SELECTION
-		parentIds := pop.selectParents(&settings)
-
-		// CROSSOVER & MUTATION
-		// TODO: for elitism << len(pop) it is more efficient to extract smallest n instead of sorting
-		if settings.Elitism > 0 {
-			sort.Sort(&pop)
-		}
-		for idx := settings.Elitism; idx < len(pop); idx++ {
-			parent1 := pop[parentIds[rand.Intn(len(parentIds))]].genome
-			parent2 := pop[parentIds[rand.Intn(len(parentIds))]].genome
-			if rand.Float64() < settings.MutationRate {
-				pop[idx].genome = parent1.Crossover(parent2).Mutate()
-			} else {
-				pop[idx].genome = parent1.Crossover(parent2)
-			}
-			pop[idx].fitness = evaluate(pop[idx].genome)
-		}
-		res.Iterations++
+		is.evolve()
 	}
 	logger.Flush()
+	res = is.res
 	res.Runtime = time.Since(start)
 	if settings.Verbose > 0 {
 		fmt.Printf("DONE after %v\n", res.Runtime)
@@ -0,0 +1,77 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+)
+
+// RateAdaptation encodes different variants of mutation and crossover rate
+// control in GA
+type RateAdaptation int
+
+const (
+	// FixedRates keeps MutationRate and CrossoverRate constant during the run
+	FixedRates RateAdaptation = iota
+	// OneFifthAdaptation follows the 1/5th success rule. When more than a fifth
+	// of the offspring is better than its parents, the mutation rate is increased
+	// to explore more, otherwise it is decreased. The crossover rate is constant
+	OneFifthAdaptation
+	// DiversityAdaptation increases the mutation rate and decreases the crossover
+	// rate when the population diversity falls below TargetDiversity and vice versa
+	DiversityAdaptation
+	// SelfAdaptation lets every genome carry its own rates. Offspring inherit the
+	// mean rates of their parents, perturbed by log-normal noise. Good rates
+	// survive together with the genomes they produced
+	SelfAdaptation
+)
+
+// minAdaptiveRate is the lower bound for adapted rates, which keeps rates from
+// getting stuck at 0
+const minAdaptiveRate = 0.001
+
+// clampRate restricts rate to [minAdaptiveRate, 1]
+func clampRate(rate float64) float64 {
+	return math.Max(minAdaptiveRate, math.Min(1.0, rate))
+}
+
+// selfAdaptedRates returns the log-normally perturbed mean rates of both parents
+func selfAdaptedRates(parent1, parent2 candidate, tau float64) (mutationRate, crossoverRate float64) {
+	mutationRate = 0.5 * (parent1.mutationRate + parent2.mutationRate) * math.Exp(tau*rand.NormFloat64())
+	crossoverRate = 0.5 * (parent1.crossoverRate + parent2.crossoverRate) * math.Exp(tau*rand.NormFloat64())
+	return clampRate(mutationRate), clampRate(crossoverRate)
+}
+
+// adaptRates updates the rates of the island after a generation, where
+// successes out of n offspring were better than their parents
+func (is *island) adaptRates(successes, n int) {
+	factor := is.settings.AdaptationFactor
+	switch is.settings.Adaptation {
+	case OneFifthAdaptation:
+		if n == 0 {
+			return
+		}
+		successRate := float64(successes) / float64(n)
+		if successRate > 0.2 {
+			is.mutationRate = clampRate(is.mutationRate / factor)
+		} else if successRate < 0.2 {
+			is.mutationRate = clampRate(is.mutationRate * factor)
+		}
+	case DiversityAdaptation:
+		if is.pop.diversity() < is.settings.TargetDiversity {
+			is.mutationRate = clampRate(is.mutationRate / factor)
+			is.crossoverRate = clampRate(is.crossoverRate * factor)
+		} else {
+			is.mutationRate = clampRate(is.mutationRate * factor)
+			is.crossoverRate = clampRate(is.crossoverRate / factor)
+		}
+	case SelfAdaptation:
+		// the island rates are the mean rates of the population
+		is.mutationRate, is.crossoverRate = 0.0, 0.0
+		for _, c := range is.pop {
+			is.mutationRate += c.mutationRate
+			is.crossoverRate += c.crossoverRate
+		}
+		is.mutationRate /= float64(len(is.pop))
+		is.crossoverRate /= float64(len(is.pop))
+	}
+}
@@ -0,0 +1,97 @@
+package hego
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestVerifyRateAdaptation(t *testing.T) {
+	settings := GASettings{}
+	settings.CrossoverRate = 1.5
+	if settings.Verify() == nil {
+		t.Error("verification should fail for invalid crossover rate")
+	}
+	settings.CrossoverRate = 0.8
+	settings.Adaptation = OneFifthAdaptation
+	settings.AdaptationFactor = 1.0
+	if settings.Verify() == nil {
+		t.Error("verification should fail for adaptation factor of 1.0 with OneFifthAdaptation")
+	}
+	settings.AdaptationFactor = 0.9
+	if err := settings.Verify(); err != nil {
+		t.Errorf("verification should pass for valid adaptation settings, got %v", err)
+	}
+	settings.Adaptation = DiversityAdaptation
+	if settings.Verify() == nil {
+		t.Error("verification should fail for DiversityAdaptation without target diversity")
+	}
+	settings.Adaptation = SelfAdaptation
+	settings.AdaptationFactor = 0.0
+	if settings.Verify() == nil {
+		t.Error("verification should fail for SelfAdaptation without learning rate")
+	}
+	settings.Adaptation = RateAdaptation(10)
+	if settings.Verify() == nil {
+		t.Error("verification should fail for unknown adaptation")
+	}
+}
+
+func TestAdaptRates(t *testing.T) {
+	settings := GASettings{Adaptation: OneFifthAdaptation, AdaptationFactor: 0.5}
+	is := island{settings: &settings, mutationRate: 0.2, crossoverRate: 1.0}
+	is.adaptRates(5, 10)
+	if is.mutationRate != 0.4 {
+		t.Errorf("mutation rate should increase for high success rate, got %v", is.mutationRate)
+	}
+	is.adaptRates(0, 10)
+	if is.mutationRate != 0.2 {
+		t.Errorf("mutation rate should decrease for low success rate, got %v", is.mutationRate)
+	}
+	is.mutationRate = 0.8
+	is.adaptRates(10, 10)
+	if is.mutationRate != 1.0 {
+		t.Errorf("mutation rate should be clamped to 1.0, got %v", is.mutationRate)
+	}
+
+	settings = GASettings{Adaptation: DiversityAdaptation, AdaptationFactor: 0.5, TargetDiversity: 1.0}
+	is = island{settings: &settings, mutationRate: 0.2, crossoverRate: 0.5}
+	is.pop = population{{genome: intGenome(1)}, {genome: intGenome(1)}}
+	is.adaptRates(0, 2)
+	if is.mutationRate != 0.4 || is.crossoverRate != 0.25 {
+		t.Errorf("low diversity should increase mutation and decrease crossover rate, got %v and %v", is.mutationRate, is.crossoverRate)
+	}
+
+	settings = GASettings{Adaptation: SelfAdaptation, AdaptationFactor: 0.1}
+	is = island{settings: &settings}
+	is.pop = population{{mutationRate: 0.2, crossoverRate: 0.4}, {mutationRate: 0.4, crossoverRate: 0.8}}
+	is.adaptRates(0, 2)
+	if is.mutationRate < 0.3-1e-10 || is.mutationRate > 0.3+1e-10 || is.crossoverRate < 0.6-1e-10 || is.crossoverRate > 0.6+1e-10 {
+		t.Errorf("island rates should be population means, got %v and %v", is.mutationRate, is.crossoverRate)
+	}
+}
+
+func TestGAAdaptation(t *testing.T) {
+	population := make([]Genome, 10)
+	for i := range population {
+		population[i] = intGenome(rand.Intn(20) - 10)
+	}
+	settings := GASettings{}
+	settings.MutationRate = 0.5
+	settings.MaxIterations = 20
+	settings.KeepHistory = true
+	settings.AdaptationFactor = 0.9
+	settings.TargetDiversity = 2.0
+	for _, adaptation := range []RateAdaptation{FixedRates, OneFifthAdaptation, DiversityAdaptation, SelfAdaptation} {
+		settings.Adaptation = adaptation
+		res, err := GA(population, settings)
+		if err != nil {
+			t.Errorf("Error while running GA with adaptation %v: %v", adaptation, err)
+		}
+		if len(res.MutationRates) != settings.MaxIterations || len(res.CrossoverRates) != settings.MaxIterations {
+			t.Errorf("expected rates for every iteration, got %v and %v", len(res.MutationRates), len(res.CrossoverRates))
+		}
+		if res.MutationRates[0] != settings.MutationRate || res.CrossoverRates[0] != 1.0 {
+			t.Errorf("expected initial rates %v and 1.0, got %v and %v", settings.MutationRate, res.MutationRates[0], res.CrossoverRates[0])
+		}
+	}
+}
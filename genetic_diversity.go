@@ -0,0 +1,72 @@
+package hego
+
+// Distancer can be implemented by a Genome to enable diversity statistics and
+// the diversity preserving mechanisms of GA (fitness sharing, crowding and
+// duplicate elimination)
+type Distancer interface {
+	// Distance returns a non negative distance to the other genome. Identical
+	// genomes have distance 0
+	Distance(other Genome) float64
+}
+
+// maxDuplicateRetries limits the number of mutations applied to an offspring
+// that duplicates a genome of the next generation
+const maxDuplicateRetries = 10
+
+// diversity returns the mean pairwise distance of the population. Genomes must
+// implement Distancer
+func (p population) diversity() float64 {
+	if len(p) < 2 {
+		return 0.0
+	}
+	total := 0.0
+	for i := range p {
+		d := p[i].genome.(Distancer)
+		for j := i + 1; j < len(p); j++ {
+			total += d.Distance(p[j].genome)
+		}
+	}
+	return total / float64(len(p)*(len(p)-1)/2)
+}
+
+// shared returns a copy of the population with shared fitness values. The
+// fitness of each genome is scaled by its niche count, the sum of
+// 1 - distance/radius over all genomes closer than radius (including itself).
+// Since lower is better, positive fitness values are multiplied and negative
+// values are divided by the niche count. Genomes must implement Distancer
+func (p population) shared(radius float64) population {
+	res := make(population, len(p))
+	for i := range p {
+		d := p[i].genome.(Distancer)
+		niche := 0.0
+		for j := range p {
+			distance := d.Distance(p[j].genome)
+			if distance < radius {
+				niche += 1.0 - distance/radius
+			}
+		}
+		if niche < 1.0 {
+			niche = 1.0
+		}
+		res[i] = p[i]
+		res[i].raw = p[i].fitness
+		if p[i].fitness >= 0.0 {
+			res[i].fitness = p[i].fitness * niche
+		} else {
+			res[i].fitness = p[i].fitness / niche
+		}
+	}
+	return res
+}
+
+// contains returns true if a genome in the population is identical to g.
+// Genomes must implement Distancer
+func (p population) contains(g Genome) bool {
+	d := g.(Distancer)
+	for _, c := range p {
+		if d.Distance(c.genome) == 0.0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func (g intGenome) Distance(other Genome) float64 {
+	return math.Abs(float64(g - other.(intGenome)))
+}
+
+// multimodalGenome is a position in [0, 10] with equally good minima at every integer
+type multimodalGenome float64
+
+func (g multimodalGenome) Fitness() float64 {
+	return -math.Cos(2.0 * math.Pi * float64(g))
+}
+
+func (g multimodalGenome) Mutate() Genome {
+	return multimodalGenome(math.Max(0.0, math.Min(10.0, float64(g)+0.1*rand.NormFloat64())))
+}
+
+func (g multimodalGenome) Crossover(other Genome) Genome {
+	w := rand.Float64()
+	return multimodalGenome(w*float64(g) + (1.0-w)*float64(other.(multimodalGenome)))
+}
+
+func (g multimodalGenome) Distance(other Genome) float64 {
+	return math.Abs(float64(g - other.(multimodalGenome)))
+}
+
+func TestDiversity(t *testing.T) {
+	pop := population{
+		candidate{genome: intGenome(0), fitness: 0.0},
+		candidate{genome: intGenome(2), fitness: 4.0},
+		candidate{genome: intGenome(4), fitness: 16.0},
+	}
+	// distances are 2, 4 and 2
+	if d := pop.diversity(); math.Abs(d-8.0/3.0) > 1e-10 {
+		t.Errorf("expected mean pairwise distance of 8/3, got %v", d)
+	}
+	if !pop.contains(intGenome(2)) {
+		t.Error("population should contain genome 2")
+	}
+	if pop.contains(intGenome(3)) {
+		t.Error("population should not contain genome 3")
+	}
+}
+
+func TestShared(t *testing.T) {
+	pop := population{
+		candidate{genome: intGenome(1), fitness: 1.0},
+		candidate{genome: intGenome(1), fitness: 1.0},
+		candidate{genome: intGenome(9), fitness: 81.0},
+	}
+	shared := pop.shared(2.0)
+	if shared[0].fitness != 2.0 {
+		t.Errorf("genomes in the same niche should share fitness, got %v", shared[0].fitness)
+	}
+	if shared[0].raw != 1.0 {
+		t.Errorf("raw fitness should be kept, got %v", shared[0].raw)
+	}
+	if shared[2].fitness != 81.0 {
+		t.Errorf("isolated genome should keep its fitness, got %v", shared[2].fitness)
+	}
+	if pop[0].fitness != 1.0 {
+		t.Error("original population should not be modified")
+	}
+}
+
+func TestGADiversity(t *testing.T) {
+	population := make([]Genome, 10)
+	for i := range population {
+		population[i] = genome(rand.Float64())
+	}
+	settings := GASettings{}
+	settings.MutationRate = 0.5
+	settings.MaxIterations = 10
+	settings.Crowding = true
+	_, err := GA(population, settings)
+	if err == nil {
+		t.Error("GA should fail for crowding with genomes not implementing Distancer")
+	}
+
+	for i := range population {
+		population[i] = intGenome(rand.Intn(20) - 10)
+	}
+	settings.SharingRadius = -1.0
+	_, err = GA(population, settings)
+	if err == nil {
+		t.Error("GA should fail for negative sharing radius")
+	}
+	settings.SharingRadius = 2.0
+	settings.EliminateDuplicates = true
+	settings.KeepHistory = true
+	res, err := GA(population, settings)
+	if err != nil {
+		t.Errorf("Error while running GA with diversity preservation: %v", err)
+	}
+	if len(res.Diversities) != settings.MaxIterations {
+		t.Errorf("expected diversity for every iteration, got %v", len(res.Diversities))
+	}
+}
+
+func TestGACrowding(t *testing.T) {
+	// finalDiversity returns the mean diversity of the last generation over several runs
+	finalDiversity := func(settings GASettings) float64 {
+		total := 0.0
+		for run := 0; run < 10; run++ {
+			population := make([]Genome, 50)
+			for i := range population {
+				population[i] = multimodalGenome(10.0 * rand.Float64())
+			}
+			res, err := GA(population, settings)
+			if err != nil {
+				t.Fatalf("Error while running GA: %v", err)
+			}
+			total += res.Diversities[len(res.Diversities)-1]
+		}
+		return total / 10.0
+	}
+	for _, selection := range []Selection{TournamentSelection, RankBasedSelection} {
+		settings := GASettings{}
+		settings.Selection = selection
+		settings.TournamentSize = 3
+		settings.MutationRate = 0.5
+		settings.MaxIterations = 100
+		settings.KeepHistory = true
+		baseline := finalDiversity(settings)
+		settings.Crowding = true
+		crowding := finalDiversity(settings)
+		// with crowding the population is spread over several minima
+		if crowding < 1.0 || crowding < 10.0*baseline {
+			t.Errorf("expected crowding to preserve diversity with selection %v, got %v compared to %v without crowding", selection, crowding, baseline)
+		}
+	}
+}
+
+// wideGenome has a large search space, so a mutation always finds a new genome
+type wideGenome int
+
+func (g wideGenome) Fitness() float64 {
+	return float64(g % 10)
+}
+
+func (g wideGenome) Mutate() Genome {
+	return wideGenome(rand.Intn(1000000))
+}
+
+// Crossover returns a copy of one parent, which is always a duplicate
+func (g wideGenome) Crossover(other Genome) Genome {
+	if rand.Float64() < 0.5 {
+		return g
+	}
+	return other
+}
+
+func (g wideGenome) Distance(other Genome) float64 {
+	return math.Abs(float64(g - other.(wideGenome)))
+}
+
+func TestGAEliminateDuplicates(t *testing.T) {
+	for _, crowding := range []bool{false, true} {
+		// without crowding every genome is replaced, so duplicates in the
+		// initial population vanish. Crowding keeps parents and starts distinct
+		population := make([]Genome, 20)
+		for i := range population {
+			population[i] = wideGenome(0)
+			if crowding {
+				population[i] = wideGenome(i)
+			}
+		}
+		settings := GASettings{}
+		settings.Selection = TournamentSelection
+		settings.TournamentSize = 2
+		settings.MaxIterations = 10
+		settings.Crowding = crowding
+		settings.EliminateDuplicates = true
+		is := newIsland(population, &settings)
+		for i := 0; i < settings.MaxIterations; i++ {
+			is.evolve()
+			for j := range is.pop {
+				if is.pop[j+1:].contains(is.pop[j].genome) {
+					t.Fatalf("expected no duplicates with crowding %v, got %v twice", crowding, is.pop[j].genome)
+				}
+			}
+		}
+	}
+}
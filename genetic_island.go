@@ -0,0 +1,231 @@
+package hego
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MigrationTopology encodes how islands exchange genomes during migration
+type MigrationTopology int
+
+const (
+	// RingMigration sends migrants from island i to island i+1, the last island
+	// sends to the first one
+	RingMigration MigrationTopology = iota
+	// FullyConnectedMigration sends migrants from every island to every other island
+	FullyConnectedMigration
+	// RandomMigration sends migrants from every island to another randomly
+	// chosen island, the target is chosen anew for every migration
+	RandomMigration
+)
+
+// IslandGAResult represents the result of the island model genetic algorithm
+type IslandGAResult struct {
+	// Islands holds the result of each island. When KeepHistory is set it
+	// contains the per island history
+	Islands     []GAResult
+	BestGenome  Genome
+	BestFitness float64
+	// LocalSearchEvaluations is the sum of local search evaluations of all islands
+	LocalSearchEvaluations int
+	Result
+}
+
+// IslandGASettings represents the settings available in the island model
+// genetic algorithm. The embedded GASettings are used for every island
+type IslandGASettings struct {
+	// Topology defines which islands exchange genomes
+	Topology MigrationTopology
+	// MigrationInterval is the number of generations between two migrations
+	MigrationInterval int
+	// MigrationSize is the number of best genomes each island sends to its
+	// neighbors. Immigrants replace the worst genomes of the receiving island
+	MigrationSize int
+	GASettings
+}
+
+// Verify returns an error, if settings are not valid
+func (s *IslandGASettings) Verify() error {
+	if err := s.GASettings.Verify(); err != nil {
+		return err
+	}
+	if s.MigrationInterval < 1 {
+		return fmt.Errorf("migration interval must be at least 1, got %v", s.MigrationInterval)
+	}
+	if s.MigrationSize < 0 {
+		return errors.New("migration size cannot be negative")
+	}
+	if s.Topology < RingMigration || s.Topology > RandomMigration {
+		return fmt.Errorf("unknown migration topology %v", s.Topology)
+	}
+	return nil
+}
+
+// migrationTargets returns the indizes of the islands receiving migrants from
+// island i
+func migrationTargets(topology MigrationTopology, i, n int) []int {
+	if n < 2 {
+		return nil
+	}
+	switch topology {
+	case RingMigration:
+		return []int{(i + 1) % n}
+	case FullyConnectedMigration:
+		targets := make([]int, 0, n-1)
+		for j := 0; j < n; j++ {
+			if j != i {
+				targets = append(targets, j)
+			}
+		}
+		return targets
+	case RandomMigration:
+		j := rand.Intn(n - 1)
+		if j >= i {
+			j++
+		}
+		return []int{j}
+	}
+	return nil
+}
+
+// migrate sends the best genomes of each island to its neighbors.
+// Each island keeps at most size of the best immigrants, which replace its
+// worst genomes
+func migrate(islands []*island, topology MigrationTopology, size int) {
+	if size == 0 {
+		return
+	}
+	immigrants := make([]population, len(islands))
+	for i, is := range islands {
+		sort.Sort(is.pop)
+		emigrants := is.pop[:size]
+		for _, target := range migrationTargets(topology, i, len(islands)) {
+			immigrants[target] = append(immigrants[target], emigrants...)
+		}
+	}
+	for i, is := range islands {
+		incoming := immigrants[i]
+		sort.Sort(incoming)
+		if len(incoming) > size {
+			incoming = incoming[:size]
+		}
+		// populations are sorted, the worst genomes are at the end
+		copy(is.pop[len(is.pop)-len(incoming):], incoming)
+	}
+}
+
+// IslandGA performs the island model genetic algorithm. Every initial
+// population is evolved on its own island by the genetic algorithm in a
+// separate goroutine. Every MigrationInterval generations the best genomes
+// migrate to the neighboring islands defined by Topology.
+// Migrants are not copied, the same genome lives on several islands whose
+// goroutines call its methods concurrently. Genome methods must not modify the
+// receiver
+func IslandGA(
+	initialPopulations [][]Genome,
+	settings IslandGASettings,
+) (res IslandGAResult, err error) {
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return
+	}
+	if len(initialPopulations) == 0 {
+		err = errors.New("at least one island is required")
+		return
+	}
+	for i, genomes := range initialPopulations {
+		err = settings.verifyGenomes(genomes)
+		if err != nil {
+			err = fmt.Errorf("island %v: %v", i, err)
+			return
+		}
+		if len(genomes) <= settings.MigrationSize || len(genomes) <= settings.Elitism {
+			err = fmt.Errorf("population of island %v is too small for migration size %v and elitism %v", i, settings.MigrationSize, settings.Elitism)
+			return
+		}
+	}
+
+	start := time.Now()
+	logger := newLogger("Island Genetic Algorithm", []string{"Iteration", "Average Fitness", "Best Fitness"}, settings.Verbose, settings.MaxIterations)
+
+	islands := make([]*island, len(initialPopulations))
+	for i := range islands {
+		islands[i] = newIsland(initialPopulations[i], &settings.GASettings)
+	}
+
+	// averages and bests hold the statistics of every island for one epoch
+	averages := make([][]float64, len(islands))
+	bests := make([][]float64, len(islands))
+	for i := range islands {
+		averages[i] = make([]float64, settings.MigrationInterval)
+		bests[i] = make([]float64, settings.MigrationInterval)
+	}
+
+	for epochStart := 0; epochStart < settings.MaxIterations; epochStart += settings.MigrationInterval {
+		epochEnd := epochStart + settings.MigrationInterval
+		if epochEnd > settings.MaxIterations {
+			epochEnd = settings.MaxIterations
+		}
+
+		var wg sync.WaitGroup
+		for idx, is := range islands {
+			wg.Add(1)
+			go func(idx int, is *island) {
+				defer wg.Done()
+				for i := epochStart; i < epochEnd; i++ {
+					averages[idx][i-epochStart], bests[idx][i-epochStart] = is.record(i)
+					is.evolve()
+				}
+			}(idx, is)
+		}
+		wg.Wait()
+
+		for i := epochStart; i < epochEnd; i++ {
+			totalFitness := 0.0
+			bestFitness := math.MaxFloat64
+			for idx := range islands {
+				totalFitness += averages[idx][i-epochStart]
+				bestFitness = math.Min(bestFitness, bests[idx][i-epochStart])
+			}
+			logger.AddLine(i, []string{
+				fmt.Sprint(i),
+				fmt.Sprint(totalFitness / float64(len(islands))),
+				fmt.Sprint(bestFitness),
+			})
+		}
+		res.Iterations = epochEnd
+
+		if epochEnd < settings.MaxIterations {
+			migrate(islands, settings.Topology, settings.MigrationSize)
+		}
+	}
+
+	res.BestFitness = math.MaxFloat64
+	res.Islands = make([]GAResult, len(islands))
+	for i, is := range islands {
+		res.Islands[i] = is.res
+		res.FuncEvaluations += is.res.FuncEvaluations
+		res.CacheHits += is.res.CacheHits
+		res.LocalSearchEvaluations += is.res.LocalSearchEvaluations
+		if is.res.BestFitness < res.BestFitness {
+			res.BestFitness = is.res.BestFitness
+			res.BestGenome = is.res.BestGenome
+		}
+	}
+
+	logger.Flush()
+	res.Runtime = time.Since(start)
+	for i := range res.Islands {
+		res.Islands[i].Runtime = res.Runtime
+	}
+	if settings.Verbose > 0 {
+		fmt.Printf("DONE after %v\n", res.Runtime)
+	}
+	return res, nil
+}
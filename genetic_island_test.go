@@ -0,0 +1,111 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVerifyIslandGASettings(t *testing.T) {
+	settings := IslandGASettings{}
+	settings.MutationRate = 0.5
+	err := settings.Verify()
+	if err == nil {
+		t.Error("verification should fail for migration interval 0")
+	}
+	settings.MigrationInterval = 5
+	settings.MigrationSize = -1
+	err = settings.Verify()
+	if err == nil {
+		t.Error("verification should fail for negative migration size")
+	}
+	settings.MigrationSize = 2
+	settings.Topology = MigrationTopology(10)
+	err = settings.Verify()
+	if err == nil {
+		t.Error("verification should fail for unknown topology")
+	}
+	settings.Topology = RandomMigration
+	err = settings.Verify()
+	if err != nil {
+		t.Errorf("for valid settings verification should pass, got: %v", err)
+	}
+}
+
+func TestMigrationTargets(t *testing.T) {
+	targets := migrationTargets(RingMigration, 3, 4)
+	if len(targets) != 1 || targets[0] != 0 {
+		t.Errorf("expected last island to send to island 0, got %v", targets)
+	}
+	targets = migrationTargets(FullyConnectedMigration, 1, 4)
+	if len(targets) != 3 {
+		t.Errorf("expected 3 targets for fully connected topology, got %v", targets)
+	}
+	for i := 0; i < 20; i++ {
+		targets = migrationTargets(RandomMigration, 2, 4)
+		if len(targets) != 1 || targets[0] == 2 {
+			t.Errorf("random target should be one other island, got %v", targets)
+		}
+	}
+	if len(migrationTargets(RingMigration, 0, 1)) != 0 {
+		t.Error("a single island should not have migration targets")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	islands := []*island{
+		{pop: population{{genome: genome(1.0), fitness: 1.0}, {genome: genome(2.0), fitness: 2.0}, {genome: genome(3.0), fitness: 3.0}}},
+		{pop: population{{genome: genome(4.0), fitness: 4.0}, {genome: genome(5.0), fitness: 5.0}, {genome: genome(6.0), fitness: 6.0}}},
+	}
+	migrate(islands, RingMigration, 1)
+	if islands[1].pop[2].fitness != 1.0 {
+		t.Errorf("expected best genome of island 0 to replace worst of island 1, got %v", islands[1].pop)
+	}
+	if islands[0].pop[2].fitness != 4.0 {
+		t.Errorf("expected best genome of island 1 to replace worst of island 0, got %v", islands[0].pop)
+	}
+}
+
+func TestIslandGA(t *testing.T) {
+	settings := IslandGASettings{}
+	_, err := IslandGA([][]Genome{}, settings)
+	if err == nil {
+		t.Error("IslandGA should fail with invalid settings")
+	}
+	settings.MutationRate = 0.1
+	settings.Elitism = 1
+	settings.MaxIterations = 100
+	settings.MigrationInterval = 7
+	settings.MigrationSize = 2
+	settings.Topology = FullyConnectedMigration
+	settings.KeepHistory = true
+
+	_, err = IslandGA([][]Genome{}, settings)
+	if err == nil {
+		t.Error("IslandGA should fail without islands")
+	}
+
+	populations := make([][]Genome, 4)
+	for i := range populations {
+		populations[i] = make([]Genome, 10)
+		for j := range populations[i] {
+			populations[i][j] = genome(-20.0 + 40.0*rand.Float64())
+		}
+	}
+	res, err := IslandGA(populations, settings)
+	if err != nil {
+		t.Errorf("Error while running island genetic algorithm: %v", err)
+	}
+	if res.Iterations != settings.MaxIterations {
+		t.Errorf("unexpected number of iterations. Expected %v, got %v", settings.MaxIterations, res.Iterations)
+	}
+	if len(res.Islands) != len(populations) {
+		t.Errorf("expected one result per island, got %v", len(res.Islands))
+	}
+	if len(res.Islands[0].BestFitnesses) != settings.MaxIterations {
+		t.Errorf("expected history for every iteration, got %v", len(res.Islands[0].BestFitnesses))
+	}
+	if math.Abs(res.BestFitness) > 0.5 {
+		t.Error("unexpected solution found")
+	}
+}
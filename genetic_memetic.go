@@ -0,0 +1,43 @@
+package hego
+
+// LocalSearcher can be implemented by a Genome to turn GA into a memetic
+// algorithm, where a fraction of the offspring is improved by local search
+type LocalSearcher interface {
+	// LocalSearch returns an improved genome in the neighborhood of this genome
+	LocalSearch() Genome
+}
+
+// LocalSearchMode encodes how GA uses the result of a local search
+type LocalSearchMode int
+
+const (
+	// LamarckianLocalSearch replaces the offspring by the improved genome, the
+	// acquired traits are passed on to the next generations
+	LamarckianLocalSearch LocalSearchMode = iota
+	// BaldwinianLocalSearch keeps the genome of the offspring but assigns the
+	// fitness of the improved genome. Local search only guides selection, the
+	// result reports the fitness of the genomes themselves
+	BaldwinianLocalSearch
+)
+
+// improve applies local search to the offspring and keeps the result when it
+// is better than the offspring
+func (is *island) improve(offspring *candidate) {
+	improved := offspring.genome.(LocalSearcher).LocalSearch()
+	fitness, ok := is.cache.lookup(improved)
+	if ok {
+		is.res.CacheHits++
+	} else {
+		is.res.LocalSearchEvaluations++
+		fitness = improved.Fitness()
+		is.cache.store(improved, fitness)
+	}
+	if fitness >= offspring.fitness {
+		return
+	}
+	if is.settings.LocalSearchMode == LamarckianLocalSearch {
+		offspring.genome = improved
+		offspring.genomeFitness = fitness
+	}
+	offspring.fitness = fitness
+}
@@ -0,0 +1,97 @@
+package hego
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func (g intGenome) LocalSearch() Genome {
+	if g > 0 {
+		return g - 1
+	}
+	if g < 0 {
+		return g + 1
+	}
+	return g
+}
+
+func TestImprove(t *testing.T) {
+	settings := GASettings{LocalSearchMode: LamarckianLocalSearch}
+	is := island{settings: &settings}
+	offspring := candidate{genome: intGenome(3), fitness: 9.0, genomeFitness: 9.0}
+	is.improve(&offspring)
+	if offspring.genome != intGenome(2) || offspring.fitness != 4.0 || offspring.genomeFitness != 4.0 {
+		t.Errorf("lamarckian local search should replace genome and fitness, got %v", offspring)
+	}
+	settings.LocalSearchMode = BaldwinianLocalSearch
+	is.improve(&offspring)
+	if offspring.genome != intGenome(2) || offspring.fitness != 1.0 || offspring.genomeFitness != 4.0 {
+		t.Errorf("baldwinian local search should only replace fitness, got %v", offspring)
+	}
+	if is.res.LocalSearchEvaluations != 2 || is.res.FuncEvaluations != 0 {
+		t.Errorf("local search evaluations should be counted separately, got %v and %v", is.res.LocalSearchEvaluations, is.res.FuncEvaluations)
+	}
+}
+
+func TestMemeticGA(t *testing.T) {
+	population := make([]Genome, 10)
+	for i := range population {
+		population[i] = genome(rand.Float64())
+	}
+	settings := GASettings{}
+	settings.MutationRate = 0.5
+	settings.MaxIterations = 20
+	settings.LocalSearchRate = 0.5
+	_, err := GA(population, settings)
+	if err == nil {
+		t.Error("GA should fail for local search with genomes not implementing LocalSearcher")
+	}
+	settings.LocalSearchRate = 1.5
+	if settings.Verify() == nil {
+		t.Error("verification should fail for invalid local search rate")
+	}
+	settings.LocalSearchRate = 0.5
+	settings.LocalSearchMode = LocalSearchMode(10)
+	if settings.Verify() == nil {
+		t.Error("verification should fail for unknown local search mode")
+	}
+	settings.LocalSearchMode = BaldwinianLocalSearch
+	for i := range population {
+		population[i] = intGenome(rand.Intn(200) - 100)
+	}
+	res, err := GA(population, settings)
+	if err != nil {
+		t.Errorf("Error while running memetic GA: %v", err)
+	}
+	if res.LocalSearchEvaluations == 0 {
+		t.Error("expected local search evaluations")
+	}
+}
+
+func TestBaldwinianResult(t *testing.T) {
+	settings := GASettings{}
+	settings.MutationRate = 0.5
+	settings.Elitism = 1
+	settings.MaxIterations = 10
+	settings.KeepHistory = true
+	settings.LocalSearchRate = 1.0
+	settings.LocalSearchMode = BaldwinianLocalSearch
+	for run := 0; run < 50; run++ {
+		population := make([]Genome, 10)
+		for i := range population {
+			population[i] = intGenome(rand.Intn(200) - 100)
+		}
+		res, err := GA(population, settings)
+		if err != nil {
+			t.Fatalf("Error while running memetic GA: %v", err)
+		}
+		if res.BestGenome.Fitness() != res.BestFitness {
+			t.Fatalf("best fitness %v does not belong to best genome %v", res.BestFitness, res.BestGenome)
+		}
+		for i, g := range res.BestGenomes {
+			if g.Fitness() != res.BestFitnesses[i] {
+				t.Fatalf("best fitness %v of iteration %v does not belong to genome %v", res.BestFitnesses[i], i, g)
+			}
+		}
+	}
+}
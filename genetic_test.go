@@ -1,6 +1,7 @@
 package hego
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 	"testing"
@@ -166,3 +167,50 @@ func TestSelections(t *testing.T) {
 		t.Error("expected population to be sorted after rank based selection")
 	}
 }
+
+type intGenome int
+
+func (g intGenome) Crossover(other Genome) Genome {
+	if rand.Float64() < 0.5 {
+		return g
+	}
+	return other
+}
+
+func (g intGenome) Fitness() float64 {
+	return float64(g * g)
+}
+
+func (g intGenome) Mutate() Genome {
+	return g + intGenome(rand.Intn(3)-1)
+}
+
+func (g intGenome) Key() string {
+	return fmt.Sprint(int(g))
+}
+
+func TestGACache(t *testing.T) {
+	population := make([]Genome, 10)
+	for i := range population {
+		population[i] = intGenome(rand.Intn(20) - 10)
+	}
+	settings := GASettings{}
+	settings.MutationRate = 0.5
+	settings.MaxIterations = 50
+	settings.CacheSize = -1
+	_, err := GA(population, settings)
+	if err == nil {
+		t.Error("GA should fail with negative cache size")
+	}
+	settings.CacheSize = 100
+	res, err := GA(population, settings)
+	if err != nil {
+		t.Errorf("Error while running GA with cache: %v", err)
+	}
+	if res.CacheHits == 0 {
+		t.Error("expected cache hits for repeated integer genomes")
+	}
+	if res.CacheHits+res.FuncEvaluations != len(population)*(settings.MaxIterations+1) {
+		t.Errorf("cache hits and evaluations should add up to the number of fitness calls, got %v + %v", res.CacheHits, res.FuncEvaluations)
+	}
+}
@@ -0,0 +1,251 @@
+package hego
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// GRASPState describes a partial or complete solution of a greedy randomized
+// adaptive search procedure. The state passed to GRASP is the empty solution
+// every construction starts from
+type GRASPState interface {
+	// Candidates returns the greedy cost of every element that can be added to
+	// the partial solution, lower costs are more attractive. An infinite cost
+	// marks an infeasible element. An empty slice marks a complete solution
+	Candidates() []float64
+	// Add returns a new state with the i-th candidate added to the solution
+	Add(i int) GRASPState
+	// Objective is the function to be minimized. It is only called on complete solutions
+	Objective() float64
+	// Neighbor produces a related complete solution for the local search
+	Neighbor() GRASPState
+}
+
+// RelinkableState can be implemented by a GRASPState to enable path relinking
+type RelinkableState interface {
+	// StepToward returns a state that is one move closer to target. It returns
+	// false, when the state already equals target
+	StepToward(target GRASPState) (GRASPState, bool)
+}
+
+// GRASPResult holds result and progress information about the greedy
+// randomized adaptive search procedure
+type GRASPResult struct {
+	// States holds the best states. Last element in this list is overall best solution
+	States []GRASPState
+	// Objectives holds the best objectives. Each entry corresponds to an element in States
+	Objectives    []float64
+	BestState     GRASPState
+	BestObjective float64
+	// Elite holds the pool of elite solutions used for path relinking
+	Elite []GRASPState
+	Result
+}
+
+// GRASPSettings describes the necessary settings for the greedy randomized
+// adaptive search procedure
+type GRASPSettings struct {
+	// Alpha controls the restricted candidate list. Candidates with a cost up to
+	// min + Alpha * (max - min) are chosen with equal probability. 0 is pure greedy
+	// construction, 1 is random construction
+	Alpha float64
+	// LocalSearchSize is the number of consecutive neighbors without improvement
+	// after which the local search stops. 0 disables the local search
+	LocalSearchSize int
+	// EliteSize is the number of elite solutions kept for path relinking. When
+	// greater than 0, states must implement RelinkableState
+	EliteSize int
+	// CacheSize is the number of objective values kept in a least recently used
+	// cache. It is only used for states implementing Keyed. 0 disables the cache
+	CacheSize int
+	Settings
+}
+
+// Verify returns an error if settings verification fails
+func (s *GRASPSettings) Verify() error {
+	if s.Alpha < 0.0 || s.Alpha > 1.0 {
+		return fmt.Errorf("alpha must be in [0, 1], got %v", s.Alpha)
+	}
+	if s.LocalSearchSize < 0 {
+		return fmt.Errorf("local search size cannot be negative, got %v", s.LocalSearchSize)
+	}
+	if s.EliteSize < 0 {
+		return fmt.Errorf("elite size cannot be negative, got %v", s.EliteSize)
+	}
+	if s.CacheSize < 0 {
+		return fmt.Errorf("cache size cannot be negative, got %v", s.CacheSize)
+	}
+	return nil
+}
+
+// restrictedCandidate chooses a random index from the restricted candidate
+// list of costs controlled by alpha. Infinite and NaN costs mark infeasible
+// candidates that are never chosen. It returns -1 when no candidate is feasible
+func restrictedCandidate(costs []float64, alpha float64) int {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, c := range costs {
+		if math.IsInf(c, 0) || math.IsNaN(c) {
+			continue
+		}
+		min, max = math.Min(min, c), math.Max(max, c)
+	}
+	if math.IsInf(min, 1) {
+		return -1
+	}
+	threshold := min + alpha*(max-min)
+	rcl := make([]int, 0, len(costs))
+	for i, c := range costs {
+		if !math.IsInf(c, 0) && !math.IsNaN(c) && c <= threshold {
+			rcl = append(rcl, i)
+		}
+	}
+	return rcl[rand.Intn(len(rcl))]
+}
+
+// GRASP performs the greedy randomized adaptive search procedure. Every
+// iteration constructs a solution from the empty initial state by repeatedly
+// adding a random element of the restricted candidate list, which is then
+// improved by a local search. With EliteSize > 0, the solution is relinked
+// with a random elite solution by walking the path between both and keeping
+// the best state on the way. GRASP stops with an error, when a construction
+// only has infeasible candidates left
+func GRASP(
+	initialState GRASPState,
+	settings GRASPSettings,
+) (res GRASPResult, err error) {
+
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return
+	}
+	if _, ok := initialState.(RelinkableState); settings.EliteSize > 0 && !ok {
+		err = errors.New("path relinking requires states to implement RelinkableState")
+		return
+	}
+
+	start := time.Now()
+
+	logger := newLogger("Greedy Randomized Adaptive Search", []string{"Iteration", "Objective", "Best"}, settings.Verbose, settings.MaxIterations)
+
+	cache := newEvalCache(settings.CacheSize)
+	evaluate := func(s GRASPState) float64 {
+		if obj, ok := cache.lookup(s); ok {
+			res.CacheHits++
+			return obj
+		}
+		res.FuncEvaluations++
+		obj := s.Objective()
+		cache.store(s, obj)
+		return obj
+	}
+
+	// construct adds candidates until the solution is complete. It fails when
+	// only infeasible candidates remain
+	construct := func() (GRASPState, error) {
+		state := initialState
+		for costs := state.Candidates(); len(costs) > 0; costs = state.Candidates() {
+			i := restrictedCandidate(costs, settings.Alpha)
+			if i < 0 {
+				return state, errors.New("construction failed, all candidates have an infinite cost")
+			}
+			state = state.Add(i)
+		}
+		return state, nil
+	}
+
+	localSearch := func(s GRASPState, obj float64) (GRASPState, float64) {
+		for failures := 0; failures < settings.LocalSearchSize; {
+			candidate := s.Neighbor()
+			if candidateObj := evaluate(candidate); candidateObj < obj {
+				s, obj = candidate, candidateObj
+				failures = 0
+			} else {
+				failures++
+			}
+		}
+		return s, obj
+	}
+
+	// relink walks from s to target and returns the best intermediate state
+	relink := func(s GRASPState, obj float64, target GRASPState) (GRASPState, float64) {
+		best, bestObj := s, obj
+		for current, ok := s.(RelinkableState).StepToward(target); ok; current, ok = current.(RelinkableState).StepToward(target) {
+			if currentObj := evaluate(current); currentObj < bestObj {
+				best, bestObj = current, currentObj
+			}
+		}
+		return best, bestObj
+	}
+
+	eliteObjs := make([]float64, 0, settings.EliteSize)
+	// updateElite adds s to the elite pool when it is not yet included and
+	// better than the worst elite solution
+	updateElite := func(s GRASPState, obj float64) {
+		worst := -1
+		for i, e := range res.Elite {
+			if _, differs := s.(RelinkableState).StepToward(e); !differs {
+				return
+			}
+			if worst == -1 || eliteObjs[i] > eliteObjs[worst] {
+				worst = i
+			}
+		}
+		if len(res.Elite) < settings.EliteSize {
+			res.Elite = append(res.Elite, s)
+			eliteObjs = append(eliteObjs, obj)
+		} else if obj < eliteObjs[worst] {
+			res.Elite[worst], eliteObjs[worst] = s, obj
+		}
+	}
+
+	if settings.KeepHistory {
+		res.States = make([]GRASPState, 0, settings.MaxIterations)
+		res.Objectives = make([]float64, 0, settings.MaxIterations)
+	}
+	res.BestObjective = math.MaxFloat64
+
+	for i := 0; i < settings.MaxIterations; i++ {
+		var state GRASPState
+		state, err = construct()
+		if err != nil {
+			break
+		}
+		state, obj := localSearch(state, evaluate(state))
+		if settings.EliteSize > 0 {
+			if len(res.Elite) > 0 {
+				relinked, relinkedObj := relink(state, obj, res.Elite[rand.Intn(len(res.Elite))])
+				if relinkedObj < obj {
+					state, obj = localSearch(relinked, relinkedObj)
+				}
+			}
+			updateElite(state, obj)
+		}
+
+		if obj < res.BestObjective {
+			res.BestObjective = obj
+			res.BestState = state
+			if settings.KeepHistory {
+				res.States = append(res.States, state)
+				res.Objectives = append(res.Objectives, obj)
+			}
+		}
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(obj),
+			fmt.Sprint(res.BestObjective),
+		})
+		res.Iterations++
+	}
+
+	res.Runtime = time.Since(start)
+
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return
+}
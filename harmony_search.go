@@ -0,0 +1,148 @@
+package hego
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// HSResult represents the result of the harmony search algorithm
+type HSResult struct {
+	BestCandidates [][]float64
+	BestObjectives []float64
+	BestCandidate  []float64
+	BestObjective  float64
+	Result
+}
+
+// HSSettings represents settings for the harmony search algorithm
+type HSSettings struct {
+	// MemorySize is the number of harmonies in the harmony memory
+	MemorySize int
+	// MemoryRate in [0, 1] is the probability to take a value from the harmony
+	// memory. Otherwise the value is taken from a random vector created by init
+	MemoryRate float64
+	// PitchRate in [0, 1] is the probability to adjust a value taken from memory
+	PitchRate float64
+	// Bandwidth is the maximum pitch adjustment of a value
+	Bandwidth float64
+	Bounds
+	Settings
+}
+
+// Verify checks the validity of the settings and returns nil if everything is ok
+func (s *HSSettings) Verify() error {
+	if s.MemorySize < 1 {
+		return fmt.Errorf("memory size must be greater than 0, got %v", s.MemorySize)
+	}
+	if s.MemoryRate < 0.0 || s.MemoryRate > 1.0 {
+		return fmt.Errorf("memory rate must be in [0, 1], got %v", s.MemoryRate)
+	}
+	if s.PitchRate < 0.0 || s.PitchRate > 1.0 {
+		return fmt.Errorf("pitch rate must be in [0, 1], got %v", s.PitchRate)
+	}
+	if s.Bandwidth < 0.0 {
+		return fmt.Errorf("bandwidth must not be negative, got %v", s.Bandwidth)
+	}
+	return s.Bounds.Verify()
+}
+
+// HS performs Harmony Search for minimizing a real valued function
+// (objective). init is called to create the harmonies of the initial memory
+// and to provide random values during improvisation. Every iteration
+// improvises a new harmony value by value from the memory, with pitch
+// adjustment, or at random. It replaces the worst harmony in memory if it is
+// better
+func HS(
+	objective func(x []float64) float64,
+	init func() []float64,
+	settings HSSettings) (res HSResult, err error) {
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return res, err
+	}
+	start := time.Now()
+	logger := newLogger("Harmony Search", []string{"Iteration", "Worst", "Best"}, settings.Verbose, settings.MaxIterations)
+	// increase funcEvaluations counter for every call to objective
+	evaluate := func(x []float64) float64 {
+		res.FuncEvaluations++
+		return objective(x) + settings.penalty(x)
+	}
+	if settings.KeepHistory {
+		res.BestCandidates = make([][]float64, 0, settings.MaxIterations)
+		res.BestObjectives = make([]float64, 0, settings.MaxIterations)
+	}
+
+	memory := make([][]float64, settings.MemorySize)
+	objs := make([]float64, settings.MemorySize)
+	best, worst := 0, 0
+	for i := range memory {
+		memory[i] = init()
+		if err = settings.verifyDimension(len(memory[i])); err != nil {
+			return res, err
+		}
+		settings.repair(memory[i], nil)
+		objs[i] = evaluate(memory[i])
+		if objs[i] < objs[best] {
+			best = i
+		}
+		if objs[i] > objs[worst] {
+			worst = i
+		}
+	}
+
+	n := len(memory[0])
+	for i := 0; i < settings.MaxIterations; i++ {
+		harmony := make([]float64, n)
+		var random []float64
+		for d := range harmony {
+			if rand.Float64() < settings.MemoryRate {
+				harmony[d] = memory[rand.Intn(settings.MemorySize)][d]
+				if rand.Float64() < settings.PitchRate {
+					harmony[d] += settings.Bandwidth * (2.0*rand.Float64() - 1.0)
+				}
+				continue
+			}
+			if random == nil {
+				random = init()
+			}
+			harmony[d] = random[d]
+		}
+		settings.repair(harmony, nil)
+		if obj := evaluate(harmony); obj < objs[worst] {
+			memory[worst], objs[worst] = harmony, obj
+			for j := range objs {
+				if objs[j] < objs[best] {
+					best = j
+				}
+				if objs[j] > objs[worst] {
+					worst = j
+				}
+			}
+		}
+
+		if settings.KeepHistory {
+			candidate := make([]float64, n)
+			copy(candidate, memory[best])
+			res.BestCandidates = append(res.BestCandidates, candidate)
+			res.BestObjectives = append(res.BestObjectives, objs[best])
+		}
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(objs[worst]),
+			fmt.Sprint(objs[best]),
+		})
+	}
+	res.BestObjective = objs[best]
+	res.BestCandidate = make([]float64, n)
+	copy(res.BestCandidate, memory[best])
+
+	res.Runtime = time.Since(start)
+	res.Iterations = settings.MaxIterations
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVerifyHSSettings(t *testing.T) {
+	settings := HSSettings{}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with memory size 0")
+	}
+	settings.MemorySize = 10
+	settings.MemoryRate = 1.5
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with memory rate > 1")
+	}
+	settings.MemoryRate = 0.9
+	settings.PitchRate = -0.1
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with negative pitch rate")
+	}
+	settings.PitchRate = 0.3
+	settings.Bandwidth = -1.0
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with negative bandwidth")
+	}
+	settings.Bandwidth = 0.01
+	if err := settings.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+}
+
+func TestHS(t *testing.T) {
+	sphere := func(x []float64) float64 {
+		return x[0]*x[0] + x[1]*x[1] + x[2]*x[2]
+	}
+	init := func() []float64 {
+		x := make([]float64, 3)
+		for i := range x {
+			x[i] = -5.12 + 10.24*rand.Float64()
+		}
+		return x
+	}
+	settings := HSSettings{}
+	_, err := HS(sphere, init, settings)
+	if err == nil {
+		t.Error("HS should fail with invalid settings")
+	}
+	settings.MemorySize = 20
+	settings.MemoryRate = 0.95
+	settings.PitchRate = 0.3
+	settings.Bandwidth = 0.01
+	settings.MaxIterations = 20000
+	settings.KeepHistory = true
+	settings.Lower = []float64{-5.12, -5.12, -5.12}
+	settings.Upper = []float64{5.12, 5.12, 5.12}
+	res, err := HS(sphere, init, settings)
+	if err != nil {
+		t.Errorf("Unexpected error in HS: %v", err)
+	}
+	if len(res.BestObjectives) != settings.MaxIterations {
+		t.Errorf("expected history for every iteration, got %v", len(res.BestObjectives))
+	}
+	for i := 1; i < len(res.BestObjectives); i++ {
+		if res.BestObjectives[i] > res.BestObjectives[i-1] {
+			t.Fatal("best objective of the harmony memory must not increase")
+		}
+	}
+	if res.BestObjective > 1e-3 {
+		t.Errorf("HS produced unexpected result, got %v", res.BestObjective)
+	}
+	if res.FuncEvaluations != settings.MemorySize+settings.MaxIterations {
+		t.Errorf("expected one evaluation per iteration, got %v", res.FuncEvaluations)
+	}
+	for _, v := range res.BestCandidate {
+		if math.Abs(v) > 5.12 {
+			t.Errorf("best candidate %v violates the bounds", res.BestCandidate)
+		}
+	}
+}
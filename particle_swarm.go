@@ -17,6 +17,30 @@ type PSOResult struct {
 	Result
 }
 
+// SwarmTopology encodes which particles inform each other about their best
+// known positions
+type SwarmTopology int
+
+const (
+	// GlobalBestTopology (star) lets every particle drift towards the best
+	// position found by the whole swarm
+	GlobalBestTopology SwarmTopology = iota
+	// RingTopology (lbest) lets every particle drift towards the best position
+	// found by itself and its two neighbors in a ring
+	RingTopology
+	// VonNeumannTopology arranges the particles in a grid wrapped around at the
+	// edges, every particle is informed by the neighbors above, below, left and right
+	VonNeumannTopology
+	// RandomTopology lets every particle inform Informants randomly chosen
+	// particles. The informants are chosen anew after every iteration without
+	// improvement of the best position
+	RandomTopology
+	// FullyInformedTopology (FIPS) lets every particle drift towards all best
+	// positions of its ring neighbors and itself instead of only the best one.
+	// The total acceleration is ParticleWeight + GlobalWeight
+	FullyInformedTopology
+)
+
 // PSOSettings represents settings for the particle swarm optimization
 type PSOSettings struct {
 	// PopulationSize is the number of particles
@@ -29,6 +53,18 @@ type PSOSettings struct {
 	GlobalWeight float64
 	// ParticleWeight determines how much a particle should drift towards the best known position of this particle
 	ParticleWeight float64
+	// Topology defines the neighborhood of a particle. With a topology other than
+	// GlobalBestTopology, GlobalWeight is the weight of the neighborhood best
+	Topology SwarmTopology
+	// Informants is the number of particles informed by each particle in RandomTopology
+	Informants int
+	// Schedule controls Omega, ParticleWeight and GlobalWeight during the run.
+	// When nil, the constant values from the settings are used
+	Schedule PSOSchedule
+	// MaxVelocity limits every velocity component to [-MaxVelocity, MaxVelocity]
+	// when greater than 0
+	MaxVelocity float64
+	Bounds
 	Settings
 }
 
@@ -52,9 +88,73 @@ func (s *PSOSettings) Verify() error {
 	if s.ParticleWeight == 0.0 && s.GlobalWeight == 0.0 {
 		return errors.New("when ParticleWeight and GlobalWeight are set to 0, the velocity will not change at all")
 	}
+	if s.MaxVelocity < 0.0 {
+		return fmt.Errorf("MaxVelocity must not be negative, got %v", s.MaxVelocity)
+	}
+	if err := s.Bounds.Verify(); err != nil {
+		return err
+	}
+	if s.Schedule != nil {
+		if err := s.Schedule.Verify(s); err != nil {
+			return err
+		}
+	}
+	if s.Topology < GlobalBestTopology || s.Topology > FullyInformedTopology {
+		return fmt.Errorf("unknown swarm topology %v", s.Topology)
+	}
+	if s.Topology == RandomTopology && (s.Informants < 1 || s.Informants >= s.PopulationSize) {
+		return fmt.Errorf("number of informants must be between 1 and population size - 1, got %v", s.Informants)
+	}
 	return nil
 }
 
+// swarmNeighbors returns the indizes of the particles informing each particle,
+// including the particle itself
+func swarmNeighbors(topology SwarmTopology, n, informants int) [][]int {
+	neighbors := make([][]int, n)
+	for i := range neighbors {
+		neighbors[i] = []int{i}
+	}
+	switch topology {
+	case GlobalBestTopology:
+		for i := range neighbors {
+			for j := 0; j < n; j++ {
+				if j != i {
+					neighbors[i] = append(neighbors[i], j)
+				}
+			}
+		}
+	case RingTopology, FullyInformedTopology:
+		for i := range neighbors {
+			neighbors[i] = append(neighbors[i], (i+n-1)%n, (i+1)%n)
+		}
+	case VonNeumannTopology:
+		cols := int(math.Ceil(math.Sqrt(float64(n))))
+		rows := (n + cols - 1) / cols
+		for i := range neighbors {
+			row, col := i/cols, i%cols
+			for _, j := range []int{
+				row*cols + (col+cols-1)%cols,
+				row*cols + (col+1)%cols,
+				((row+rows-1)%rows)*cols + col,
+				((row+1)%rows)*cols + col,
+			} {
+				if j < n && j != i {
+					neighbors[i] = append(neighbors[i], j)
+				}
+			}
+		}
+	case RandomTopology:
+		for i := 0; i < n; i++ {
+			for k := 0; k < informants; k++ {
+				j := rand.Intn(n)
+				neighbors[j] = append(neighbors[j], i)
+			}
+		}
+	}
+	return neighbors
+}
+
 // PSO performs particle swarm optimization. Objective is the function to minimize, init initializes a tupe of particle and velocity, settings holds algorithm settings
 func PSO(
 	objective func(x []float64) float64,
@@ -70,7 +170,7 @@ func PSO(
 	// increase funcEvaluations counter for every call to objective
 	evaluate := func(x []float64) float64 {
 		res.FuncEvaluations++
-		return objective(x)
+		return objective(x) + settings.penalty(x)
 	}
 	if settings.KeepHistory {
 		res.BestParticles = make([][]float64, 0, settings.MaxIterations)
@@ -89,6 +189,10 @@ func PSO(
 
 	for i := range particles {
 		particles[i], velocities[i] = init()
+		if err = settings.verifyDimension(len(particles[i])); err != nil {
+			return res, err
+		}
+		settings.repair(particles[i], nil)
 		bestObjs[i] = evaluate(particles[i])
 		bestPositions[i] = make([]float64, len(particles[i]))
 		copy(bestPositions[i], particles[i])
@@ -100,28 +204,75 @@ func PSO(
 		}
 	}
 
+	res.BestObjective = globalBestObj
+	res.BestParticle = make([]float64, len(globalBest))
+	copy(res.BestParticle, globalBest)
 	if settings.KeepHistory {
 		res.BestObjectives = append(res.BestObjectives, globalBestObj)
-		res.BestParticles = append(res.BestParticles, globalBest)
+		res.BestParticles = append(res.BestParticles, res.BestParticle)
 	}
 
+	neighbors := swarmNeighbors(settings.Topology, settings.PopulationSize, settings.Informants)
+	// localBest returns the best known position in the neighborhood of particle j
+	localBest := func(j int) []float64 {
+		if settings.Topology == GlobalBestTopology {
+			return globalBest
+		}
+		best := j
+		for _, k := range neighbors[j] {
+			if bestObjs[k] < bestObjs[best] {
+				best = k
+			}
+		}
+		return bestPositions[best]
+	}
+
+	w := settings.Omega
+	phip, phig := settings.ParticleWeight, settings.GlobalWeight
+	successRate := 1.0
+
 	for i := 0; i < settings.MaxIterations; i++ {
 		totalObj := 0.0
 		newGlobalBest := false
 		newGlobalBestParticle := make([]float64, len(globalBest))
+		successes := 0
+		if settings.Schedule != nil {
+			w, phip, phig = settings.Schedule.Parameters(&settings, PSOProgress{
+				Iteration:     i,
+				MaxIterations: settings.MaxIterations,
+				SuccessRate:   successRate,
+			})
+		}
 		for j, particle := range particles {
 			velocity := velocities[j]
-			for d, v := range velocity {
-				rp, rg := rand.Float64(), rand.Float64()
-				w := settings.Omega
-				phip, phig := settings.ParticleWeight, settings.GlobalWeight
-				velocity[d] = w*v + phip*rp*(bestPositions[j][d]-particle[d]) + phig*rg*(globalBest[d]-particle[d])
+			if settings.Topology == FullyInformedTopology {
+				// every neighbor contributes an equal share of the total acceleration
+				phi := (phip + phig) / float64(len(neighbors[j]))
+				for d, v := range velocity {
+					velocity[d] = w * v
+					for _, k := range neighbors[j] {
+						velocity[d] += phi * rand.Float64() * (bestPositions[k][d] - particle[d])
+					}
+				}
+			} else {
+				best := localBest(j)
+				for d, v := range velocity {
+					rp, rg := rand.Float64(), rand.Float64()
+					velocity[d] = w*v + phip*rp*(bestPositions[j][d]-particle[d]) + phig*rg*(best[d]-particle[d])
+				}
+			}
+			if settings.MaxVelocity > 0.0 {
+				for d, v := range velocity {
+					velocity[d] = math.Max(-settings.MaxVelocity, math.Min(settings.MaxVelocity, v))
+				}
 			}
 			for d, p := range particle {
 				particle[d] = p + settings.LearningRate*velocity[d]
 			}
+			settings.repair(particle, velocity)
 			obj := evaluate(particle)
 			if obj < bestObjs[j] {
+				successes++
 				copy(bestPositions[j], particle)
 				bestObjs[j] = obj
 				if obj < globalBestObj {
@@ -133,6 +284,10 @@ func PSO(
 			}
 			totalObj += obj
 		}
+		successRate = float64(successes) / float64(settings.PopulationSize)
+		if settings.Topology == RandomTopology && !newGlobalBest {
+			neighbors = swarmNeighbors(settings.Topology, settings.PopulationSize, settings.Informants)
+		}
 		if newGlobalBest {
 			next := make([]float64, len(globalBest))
 			copy(next, globalBest)
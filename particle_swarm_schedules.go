@@ -0,0 +1,125 @@
+package hego
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// PSOProgress describes the state of a PSO run when the parameters for the
+// next iteration are chosen
+type PSOProgress struct {
+	// Iteration is the index of the upcoming iteration
+	Iteration int
+	// MaxIterations is the total number of iterations
+	MaxIterations int
+	// SuccessRate is the fraction of particles that improved their best known
+	// position in the last iteration. It is 1 before the first iteration
+	SuccessRate float64
+}
+
+// PSOSchedule controls the inertia weight and the acceleration coefficients of
+// PSO. It is queried once per iteration
+type PSOSchedule interface {
+	// Verify checks the schedule against the settings and returns nil if everything is ok
+	Verify(settings *PSOSettings) error
+	// Parameters returns the inertia weight omega and the weights of the
+	// particles best and the neighborhood best position for the next iteration
+	Parameters(settings *PSOSettings, progress PSOProgress) (omega, particleWeight, globalWeight float64)
+}
+
+// LinearInertia decreases the inertia weight linearly from Start to End over
+// the run, moving from exploration to exploitation. The acceleration
+// coefficients are taken from the settings
+type LinearInertia struct {
+	Start float64
+	End   float64
+}
+
+// Verify checks the validity of the schedule and returns nil if everything is ok
+func (l *LinearInertia) Verify(settings *PSOSettings) error {
+	if l.Start < 0.0 || l.End < 0.0 {
+		return fmt.Errorf("inertia weights must not be negative, got %v and %v", l.Start, l.End)
+	}
+	return nil
+}
+
+// Parameters returns the linearly interpolated inertia weight
+func (l *LinearInertia) Parameters(settings *PSOSettings, progress PSOProgress) (float64, float64, float64) {
+	omega := l.Start - (l.Start-l.End)*float64(progress.Iteration)/float64(progress.MaxIterations)
+	return omega, settings.ParticleWeight, settings.GlobalWeight
+}
+
+// ChaoticInertia decreases the inertia weight linearly from Start to End and
+// scales the final weight with a logistic map z = 4z(1 - z). The chaotic
+// sequence keeps the swarm exploring around the end of the run
+type ChaoticInertia struct {
+	Start float64
+	End   float64
+	// z is the state of the logistic map
+	z float64
+}
+
+// Verify checks the validity of the schedule and returns nil if everything is ok
+func (c *ChaoticInertia) Verify(settings *PSOSettings) error {
+	if c.Start < 0.0 || c.End < 0.0 {
+		return fmt.Errorf("inertia weights must not be negative, got %v and %v", c.Start, c.End)
+	}
+	return nil
+}
+
+// Parameters advances the logistic map and returns the chaotic inertia weight
+func (c *ChaoticInertia) Parameters(settings *PSOSettings, progress PSOProgress) (float64, float64, float64) {
+	// avoid the fixed points and cycles of the logistic map
+	for c.z == 0.0 || c.z == 0.25 || c.z == 0.5 || c.z == 0.75 || c.z == 1.0 {
+		c.z = rand.Float64()
+	}
+	c.z = 4.0 * c.z * (1.0 - c.z)
+	remaining := float64(progress.MaxIterations-progress.Iteration) / float64(progress.MaxIterations)
+	omega := (c.Start-c.End)*remaining + c.End*c.z
+	return omega, settings.ParticleWeight, settings.GlobalWeight
+}
+
+// ConstrictionFactor implements the constriction coefficient of Clerc and
+// Kennedy. With phi = ParticleWeight + GlobalWeight > 4, the velocity update
+// is scaled by chi = 2 / |2 - phi - sqrt(phi^2 - 4 phi)|, which guarantees
+// convergence of the swarm. The common choice is ParticleWeight = GlobalWeight = 2.05
+type ConstrictionFactor struct{}
+
+// Verify checks that the acceleration coefficients sum up to more than 4
+func (c *ConstrictionFactor) Verify(settings *PSOSettings) error {
+	if phi := settings.ParticleWeight + settings.GlobalWeight; phi <= 4.0 {
+		return fmt.Errorf("constriction factor requires ParticleWeight + GlobalWeight > 4, got %v", phi)
+	}
+	return nil
+}
+
+// Parameters returns the constriction coefficient as inertia weight and the
+// constricted acceleration coefficients
+func (c *ConstrictionFactor) Parameters(settings *PSOSettings, progress PSOProgress) (float64, float64, float64) {
+	phi := settings.ParticleWeight + settings.GlobalWeight
+	chi := 2.0 / math.Abs(2.0-phi-math.Sqrt(phi*phi-4.0*phi))
+	return chi, chi * settings.ParticleWeight, chi * settings.GlobalWeight
+}
+
+// AdaptiveInertia chooses the inertia weight from [Min, Max] proportional to
+// the success rate of the swarm. A successful swarm keeps its momentum, while
+// a stagnating swarm slows down and refines around the best known positions
+type AdaptiveInertia struct {
+	Min float64
+	Max float64
+}
+
+// Verify checks the validity of the schedule and returns nil if everything is ok
+func (a *AdaptiveInertia) Verify(settings *PSOSettings) error {
+	if a.Min < 0.0 || a.Max < a.Min {
+		return fmt.Errorf("inertia weights must satisfy 0 <= Min <= Max, got %v and %v", a.Min, a.Max)
+	}
+	return nil
+}
+
+// Parameters returns the inertia weight for the current success rate
+func (a *AdaptiveInertia) Parameters(settings *PSOSettings, progress PSOProgress) (float64, float64, float64) {
+	omega := a.Min + (a.Max-a.Min)*progress.SuccessRate
+	return omega, settings.ParticleWeight, settings.GlobalWeight
+}
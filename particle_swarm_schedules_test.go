@@ -0,0 +1,108 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLinearInertia(t *testing.T) {
+	settings := PSOSettings{ParticleWeight: 1.0, GlobalWeight: 2.0}
+	l := &LinearInertia{Start: 0.9, End: 0.4}
+	w, pw, gw := l.Parameters(&settings, PSOProgress{Iteration: 0, MaxIterations: 10})
+	if w != 0.9 || pw != 1.0 || gw != 2.0 {
+		t.Errorf("expected (0.9, 1, 2) in first iteration, got (%v, %v, %v)", w, pw, gw)
+	}
+	w, _, _ = l.Parameters(&settings, PSOProgress{Iteration: 5, MaxIterations: 10})
+	if math.Abs(w-0.65) > 1e-12 {
+		t.Errorf("expected inertia 0.65 after half of the run, got %v", w)
+	}
+	l.End = -1.0
+	if l.Verify(&settings) == nil {
+		t.Error("expected verification to fail with negative inertia")
+	}
+}
+
+func TestChaoticInertia(t *testing.T) {
+	settings := PSOSettings{}
+	c := &ChaoticInertia{Start: 0.9, End: 0.4}
+	previous := -1.0
+	for i := 0; i < 10; i++ {
+		w, _, _ := c.Parameters(&settings, PSOProgress{Iteration: 10, MaxIterations: 10})
+		if w < 0.0 || w > 0.4 {
+			t.Errorf("expected inertia in [0, 0.4] at the end of the run, got %v", w)
+		}
+		if w == previous {
+			t.Errorf("expected chaotic sequence to change, got %v twice", w)
+		}
+		previous = w
+	}
+}
+
+func TestConstrictionFactor(t *testing.T) {
+	settings := PSOSettings{ParticleWeight: 2.05, GlobalWeight: 2.05}
+	c := &ConstrictionFactor{}
+	if err := c.Verify(&settings); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+	w, pw, gw := c.Parameters(&settings, PSOProgress{})
+	if math.Abs(w-0.7298) > 1e-4 || math.Abs(pw-1.4962) > 1e-4 || math.Abs(gw-1.4962) > 1e-4 {
+		t.Errorf("expected (0.7298, 1.4962, 1.4962), got (%v, %v, %v)", w, pw, gw)
+	}
+	settings.GlobalWeight = 1.0
+	if c.Verify(&settings) == nil {
+		t.Error("expected verification to fail with phi <= 4")
+	}
+}
+
+func TestAdaptiveInertia(t *testing.T) {
+	settings := PSOSettings{}
+	a := &AdaptiveInertia{Min: 0.2, Max: 1.0}
+	w, _, _ := a.Parameters(&settings, PSOProgress{SuccessRate: 0.5})
+	if math.Abs(w-0.6) > 1e-12 {
+		t.Errorf("expected inertia 0.6 for success rate 0.5, got %v", w)
+	}
+	a.Max = 0.1
+	if a.Verify(&settings) == nil {
+		t.Error("expected verification to fail with Max < Min")
+	}
+}
+
+func TestPSOSchedules(t *testing.T) {
+	f := func(x []float64) float64 {
+		return x[0]*x[0] + x[1]*x[1]
+	}
+	init := func() ([]float64, []float64) {
+		return []float64{-10 + rand.Float64()*20, -10 + rand.Float64()*20}, []float64{rand.Float64(), rand.Float64()}
+	}
+	settings := PSOSettings{}
+	settings.MaxIterations = 200
+	settings.LearningRate = 1.0
+	settings.GlobalWeight = 2.05
+	settings.ParticleWeight = 2.05
+	settings.PopulationSize = 20
+	settings.Schedule = &ConstrictionFactor{}
+	res, err := PSO(f, init, settings)
+	if err != nil {
+		t.Errorf("PSO should not fail, got: %v", err)
+	}
+	if res.BestObjective > 0.01 {
+		t.Errorf("PSO with constriction factor produced unexpected result, got %v", res.BestObjective)
+	}
+
+	settings.GlobalWeight = 1.5
+	settings.ParticleWeight = 1.5
+	if _, err = PSO(f, init, settings); err == nil {
+		t.Error("PSO should fail when the schedule verification fails")
+	}
+	for _, schedule := range []PSOSchedule{&LinearInertia{Start: 0.9, End: 0.4}, &ChaoticInertia{Start: 0.9, End: 0.4}, &AdaptiveInertia{Min: 0.2, Max: 0.9}} {
+		settings.Schedule = schedule
+		res, err = PSO(f, init, settings)
+		if err != nil {
+			t.Errorf("PSO should not fail, got: %v", err)
+		}
+		if res.BestObjective > 0.01 {
+			t.Errorf("PSO with schedule %T produced unexpected result, got %v", schedule, res.BestObjective)
+		}
+	}
+}
@@ -102,3 +102,124 @@ func TestPSO(t *testing.T) {
 		t.Error("expected BestParticles to contain values, got 0")
 	}
 }
+
+func TestVerifyPSOTopology(t *testing.T) {
+	settings := PSOSettings{}
+	settings.PopulationSize = 10
+	settings.LearningRate = 0.1
+	settings.GlobalWeight = 0.1
+	settings.Topology = SwarmTopology(10)
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with unknown topology")
+	}
+	settings.Topology = RandomTopology
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with random topology and 0 informants")
+	}
+	settings.Informants = 3
+	if err := settings.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+}
+
+func TestSwarmNeighbors(t *testing.T) {
+	contains := func(s []int, v int) bool {
+		for _, x := range s {
+			if x == v {
+				return true
+			}
+		}
+		return false
+	}
+	ring := swarmNeighbors(RingTopology, 5, 0)
+	if len(ring[0]) != 3 || !contains(ring[0], 4) || !contains(ring[0], 1) {
+		t.Errorf("expected ring neighbors 0, 4, 1 for particle 0, got %v", ring[0])
+	}
+	grid := swarmNeighbors(VonNeumannTopology, 9, 0)
+	for _, n := range []int{0, 2, 6, 1, 3} {
+		if !contains(grid[0], n) {
+			t.Errorf("expected %v in von Neumann neighborhood of particle 0, got %v", n, grid[0])
+		}
+	}
+	incomplete := swarmNeighbors(VonNeumannTopology, 7, 0)
+	for i, n := range incomplete {
+		for _, j := range n {
+			if j >= 7 {
+				t.Errorf("neighbor %v of particle %v is out of range", j, i)
+			}
+		}
+	}
+	random := swarmNeighbors(RandomTopology, 10, 2)
+	total := 0
+	for i, n := range random {
+		if n[0] != i {
+			t.Errorf("expected particle %v to inform itself, got %v", i, n)
+		}
+		total += len(n) - 1
+	}
+	if total != 20 {
+		t.Errorf("expected 20 informant links, got %v", total)
+	}
+}
+
+func TestPSOTopologies(t *testing.T) {
+	f := func(x []float64) float64 {
+		return x[0]*x[0] + x[1]*x[1]
+	}
+	init := func() ([]float64, []float64) {
+		return []float64{-10 + rand.Float64()*20, -10 + rand.Float64()*20}, []float64{rand.Float64(), rand.Float64()}
+	}
+	settings := PSOSettings{}
+	settings.MaxIterations = 200
+	settings.LearningRate = 1.0
+	settings.Omega = 0.7
+	settings.GlobalWeight = 1.5
+	settings.ParticleWeight = 1.5
+	settings.PopulationSize = 20
+	settings.Informants = 3
+	for _, topology := range []SwarmTopology{RingTopology, VonNeumannTopology, RandomTopology, FullyInformedTopology} {
+		settings.Topology = topology
+		res, err := PSO(f, init, settings)
+		if err != nil {
+			t.Errorf("PSO with topology %v should not fail, got: %v", topology, err)
+		}
+		if res.BestObjective > 0.01 {
+			t.Errorf("PSO with topology %v produced unexpected result, got %v", topology, res.BestObjective)
+		}
+	}
+}
+
+func TestPSOBounds(t *testing.T) {
+	// unconstrained minimum at 0 is outside of the bounds
+	f := func(x []float64) float64 {
+		return x[0] * x[0]
+	}
+	init := func() ([]float64, []float64) {
+		return []float64{-10 + rand.Float64()*20}, []float64{rand.Float64() * 20.0}
+	}
+	settings := PSOSettings{}
+	settings.MaxIterations = 100
+	settings.LearningRate = 1.0
+	settings.GlobalWeight = 0.5
+	settings.Omega = 0.7
+	settings.ParticleWeight = 0.5
+	settings.PopulationSize = 10
+	settings.MaxVelocity = 1.0
+	settings.Lower = []float64{1.0, 1.0}
+	settings.Upper = []float64{5.0, 5.0}
+	if _, err := PSO(f, init, settings); err == nil {
+		t.Error("PSO should fail when bounds do not match the particles")
+	}
+	settings.Lower = []float64{1.0}
+	settings.Upper = []float64{5.0}
+	for _, handling := range []BoundaryHandling{ClampBoundary, ReflectBoundary, WrapBoundary, ReinitBoundary} {
+		settings.Handling = handling
+		res, err := PSO(f, init, settings)
+		if err != nil {
+			t.Errorf("PSO should not fail, got: %v", err)
+		}
+		if res.BestParticle[0] < 1.0 || res.BestParticle[0] > 1.1 {
+			t.Errorf("handling %v: expected best particle close to lower bound 1.0, got %v", handling, res.BestParticle[0])
+		}
+	}
+}
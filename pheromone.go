@@ -0,0 +1,407 @@
+package hego
+
+import (
+	"math"
+	"sync"
+)
+
+// PheromoneMatrix stores the pheromone trails on the edges of a graph with
+// Size() nodes. Implementations are safe for concurrent use
+type PheromoneMatrix interface {
+	// Size returns the number of nodes
+	Size() int
+	// Get returns the amount of pheromone on the edge from i to j
+	Get(i, j int) float64
+	// Deposit adds amount to the pheromone on the edge from i to j
+	Deposit(i, j int, amount float64)
+	// Set sets the amount of pheromone on the edge from i to j
+	Set(i, j int, value float64)
+	// Evaporate multiplies every trail with factor, trails do not drop below min
+	Evaporate(factor, min float64)
+	// Clamp restricts every trail to [min, max]
+	Clamp(min, max float64)
+	// Reset sets every trail to value
+	Reset(value float64)
+}
+
+// RowReader can be implemented by a PheromoneMatrix to read all trails leaving
+// a node at once. Weights uses it to take the lock once per step of an ant
+// instead of once per edge, which keeps concurrent tour construction free of
+// lock contention
+type RowReader interface {
+	// Row copies the pheromone on the edges from i to every node into dst,
+	// which is grown to Size() if necessary, and returns it
+	Row(i int, dst []float64) []float64
+}
+
+// DensePheromones is a PheromoneMatrix storing every edge. It is the right
+// choice for complete graphs like the traveling salesman problem
+type DensePheromones struct {
+	mu     sync.RWMutex
+	size   int
+	values []float64
+}
+
+// NewDensePheromones returns a dense n x n pheromone matrix where every edge
+// holds the initial amount of pheromone
+func NewDensePheromones(n int, initial float64) *DensePheromones {
+	p := DensePheromones{size: n, values: make([]float64, n*n)}
+	for i := range p.values {
+		p.values[i] = initial
+	}
+	return &p
+}
+
+// Size returns the number of nodes
+func (p *DensePheromones) Size() int {
+	return p.size
+}
+
+// Get returns the amount of pheromone on the edge from i to j
+func (p *DensePheromones) Get(i, j int) float64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.values[i*p.size+j]
+}
+
+// Row copies the pheromone on the edges from i to every node into dst
+func (p *DensePheromones) Row(i int, dst []float64) []float64 {
+	if cap(dst) < p.size {
+		dst = make([]float64, p.size)
+	}
+	dst = dst[:p.size]
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	copy(dst, p.values[i*p.size:(i+1)*p.size])
+	return dst
+}
+
+// Deposit adds amount to the pheromone on the edge from i to j
+func (p *DensePheromones) Deposit(i, j int, amount float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.values[i*p.size+j] += amount
+}
+
+// Set sets the amount of pheromone on the edge from i to j
+func (p *DensePheromones) Set(i, j int, value float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.values[i*p.size+j] = value
+}
+
+// Evaporate multiplies every trail with factor, trails do not drop below min
+func (p *DensePheromones) Evaporate(factor, min float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range p.values {
+		p.values[i] = math.Max(min, p.values[i]*factor)
+	}
+}
+
+// Clamp restricts every trail to [min, max]
+func (p *DensePheromones) Clamp(min, max float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range p.values {
+		p.values[i] = math.Max(min, math.Min(max, p.values[i]))
+	}
+}
+
+// Reset sets every trail to value
+func (p *DensePheromones) Reset(value float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range p.values {
+		p.values[i] = value
+	}
+}
+
+// SparsePheromones is a PheromoneMatrix that only stores edges which received
+// pheromone. All other edges share a common base value. It is the right choice
+// for large graphs where ants only use a small fraction of the edges
+type SparsePheromones struct {
+	mu     sync.RWMutex
+	size   int
+	base   float64
+	values map[[2]int]float64
+}
+
+// NewSparsePheromones returns a sparse n x n pheromone matrix where every edge
+// holds the initial amount of pheromone
+func NewSparsePheromones(n int, initial float64) *SparsePheromones {
+	return &SparsePheromones{size: n, base: initial, values: make(map[[2]int]float64)}
+}
+
+// Size returns the number of nodes
+func (p *SparsePheromones) Size() int {
+	return p.size
+}
+
+// Get returns the amount of pheromone on the edge from i to j
+func (p *SparsePheromones) Get(i, j int) float64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if v, ok := p.values[[2]int{i, j}]; ok {
+		return v
+	}
+	return p.base
+}
+
+// Row copies the pheromone on the edges from i to every node into dst
+func (p *SparsePheromones) Row(i int, dst []float64) []float64 {
+	if cap(dst) < p.size {
+		dst = make([]float64, p.size)
+	}
+	dst = dst[:p.size]
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for j := range dst {
+		if v, ok := p.values[[2]int{i, j}]; ok {
+			dst[j] = v
+		} else {
+			dst[j] = p.base
+		}
+	}
+	return dst
+}
+
+// Deposit adds amount to the pheromone on the edge from i to j
+func (p *SparsePheromones) Deposit(i, j int, amount float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	edge := [2]int{i, j}
+	if v, ok := p.values[edge]; ok {
+		p.values[edge] = v + amount
+	} else {
+		p.values[edge] = p.base + amount
+	}
+}
+
+// Set sets the amount of pheromone on the edge from i to j
+func (p *SparsePheromones) Set(i, j int, value float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.values[[2]int{i, j}] = value
+}
+
+// Evaporate multiplies every trail with factor, trails do not drop below min.
+// Edges that evaporated to the base value are no longer stored
+func (p *SparsePheromones) Evaporate(factor, min float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.base = math.Max(min, p.base*factor)
+	for edge, v := range p.values {
+		p.setStored(edge, math.Max(min, v*factor))
+	}
+}
+
+// Clamp restricts every trail to [min, max]
+func (p *SparsePheromones) Clamp(min, max float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.base = math.Max(min, math.Min(max, p.base))
+	for edge, v := range p.values {
+		p.setStored(edge, math.Max(min, math.Min(max, v)))
+	}
+}
+
+// Reset sets every trail to value
+func (p *SparsePheromones) Reset(value float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.base = value
+	p.values = make(map[[2]int]float64)
+}
+
+// setStored updates a stored edge and removes it when it equals the base value.
+// The caller must hold the lock
+func (p *SparsePheromones) setStored(edge [2]int, value float64) {
+	if value == p.base {
+		delete(p.values, edge)
+	} else {
+		p.values[edge] = value
+	}
+}
+
+// Weights returns the transition weights from node from to every node j of the
+// pheromone matrix: pheromone(from, j)^alpha * heuristic(from, j)^beta.
+// Nodes where visited is true get the weight 0. heuristic and visited may be nil.
+// Matrices implementing RowReader are read with a single call
+func Weights(p PheromoneMatrix, from int, heuristic func(i, j int) float64, alpha, beta float64, visited []bool) []float64 {
+	var weights []float64
+	if r, ok := p.(RowReader); ok {
+		weights = r.Row(from, nil)
+	} else {
+		weights = make([]float64, p.Size())
+		for j := range weights {
+			weights[j] = p.Get(from, j)
+		}
+	}
+	for j := range weights {
+		if visited != nil && visited[j] {
+			weights[j] = 0.0
+			continue
+		}
+		weights[j] = math.Pow(weights[j], alpha)
+		if heuristic != nil {
+			weights[j] *= math.Pow(heuristic(from, j), beta)
+		}
+	}
+	return weights
+}
+
+// GraphAnt is a ready-made Ant that searches a tour through all nodes of a
+// graph, e.g. for the traveling salesman problem. All ants of a colony share
+// the same PheromoneMatrix, so users only supply the distance function
+type GraphAnt struct {
+	// Pheromones is the pheromone matrix shared by the colony
+	Pheromones PheromoneMatrix
+	// Distance returns the cost of the edge from i to j. The heuristic
+	// desirability of an edge is 1 / distance
+	Distance func(i, j int) float64
+	// Alpha is the exponent of the pheromone in the transition weights
+	Alpha float64
+	// Beta is the exponent of the heuristic desirability in the transition weights
+	Beta float64
+	// Q scales the pheromone deposit. Q / tour length is dropped on every edge
+	Q float64
+	// Start is the first node of every tour
+	Start int
+	// Open tours end at the last visited node instead of returning to Start
+	Open bool
+	// Symmetric drops pheromone on both directions of an edge
+	Symmetric bool
+	// LocalSearch improves every tour with TwoOpt and OrOpt before it is
+	// evaluated. Requires a symmetric distance
+	LocalSearch bool
+
+	tour    []int
+	visited []bool
+	length  float64
+	// lastStep is the index in tour where the last step started
+	lastStep int
+}
+
+// NewGraphAnt returns a GraphAnt with common parameters: Alpha = 1, Beta = 2,
+// Q = 1, a closed tour starting at node 0 and symmetric pheromone deposit
+func NewGraphAnt(pheromones PheromoneMatrix, distance func(i, j int) float64) *GraphAnt {
+	return &GraphAnt{
+		Pheromones: pheromones,
+		Distance:   distance,
+		Alpha:      1.0,
+		Beta:       2.0,
+		Q:          1.0,
+		Symmetric:  true,
+	}
+}
+
+// Init resets the ant to the start node
+func (a *GraphAnt) Init() {
+	a.tour = append(a.tour[:0], a.Start)
+	if len(a.visited) != a.Pheromones.Size() {
+		a.visited = make([]bool, a.Pheromones.Size())
+	}
+	for i := range a.visited {
+		a.visited[i] = false
+	}
+	a.visited[a.Start] = true
+	a.length = 0.0
+}
+
+// Step moves the ant to next and returns true when all nodes are visited
+func (a *GraphAnt) Step(next int) bool {
+	a.lastStep = len(a.tour) - 1
+	a.length += a.Distance(a.tour[len(a.tour)-1], next)
+	a.tour = append(a.tour, next)
+	a.visited[next] = true
+	done := len(a.tour) == len(a.visited)
+	if done && !a.Open {
+		a.length += a.Distance(next, a.Start)
+		a.tour = append(a.tour, a.Start)
+	}
+	return done
+}
+
+// heuristic returns the desirability of an edge
+func (a *GraphAnt) heuristic(i, j int) float64 {
+	return 1.0 / math.Max(a.Distance(i, j), 1e-10)
+}
+
+// PerceivePheromone returns the transition weights from the current node,
+// visited nodes have weight 0
+func (a *GraphAnt) PerceivePheromone() []float64 {
+	return Weights(a.Pheromones, a.tour[len(a.tour)-1], a.heuristic, a.Alpha, a.Beta, a.visited)
+}
+
+// DropPheromone deposits Q / performance on every edge of the tour
+func (a *GraphAnt) DropPheromone(performance float64) {
+	amount := a.Q / math.Max(performance, 1e-10)
+	for i := 0; i < len(a.tour)-1; i++ {
+		a.Pheromones.Deposit(a.tour[i], a.tour[i+1], amount)
+		if a.Symmetric {
+			a.Pheromones.Deposit(a.tour[i+1], a.tour[i], amount)
+		}
+	}
+}
+
+// Evaporate evaporates the shared pheromone matrix
+func (a *GraphAnt) Evaporate(factor, min float64) {
+	a.Pheromones.Evaporate(factor, min)
+}
+
+// LimitPheromone restricts all trails to [min, max]. The limits are given for a
+// deposit of 1 / performance and are therefore scaled by Q
+func (a *GraphAnt) LimitPheromone(min, max float64) {
+	a.Pheromones.Clamp(min*a.Q, max*a.Q)
+}
+
+// ResetPheromone sets all trails to value, which is scaled by Q like the limits
+func (a *GraphAnt) ResetPheromone(value float64) {
+	a.Pheromones.Reset(value * a.Q)
+}
+
+// LocalUpdate pulls the trails of the edges traversed in the last step towards
+// initial
+func (a *GraphAnt) LocalUpdate(evaporation, initial float64) {
+	for k := a.lastStep; k < len(a.tour)-1; k++ {
+		i, j := a.tour[k], a.tour[k+1]
+		a.Pheromones.Set(i, j, (1-evaporation)*a.Pheromones.Get(i, j)+evaporation*initial)
+		if a.Symmetric {
+			a.Pheromones.Set(j, i, (1-evaporation)*a.Pheromones.Get(j, i)+evaporation*initial)
+		}
+	}
+}
+
+// Improve applies TwoOpt and OrOpt to the tour when LocalSearch is set
+func (a *GraphAnt) Improve() {
+	if !a.LocalSearch {
+		return
+	}
+	improved := TwoOpt(a.tour, a.Distance)
+	improved = OrOpt(a.tour, a.Distance) || improved
+	if improved {
+		a.length = TourLength(a.tour, a.Distance)
+	}
+}
+
+// Performance returns the length of the tour
+func (a *GraphAnt) Performance() float64 {
+	return a.length
+}
+
+// Clone returns a copy of the ant sharing the same pheromone matrix
+func (a *GraphAnt) Clone() Ant {
+	clone := *a
+	clone.tour = a.Tour()
+	clone.visited = make([]bool, len(a.visited))
+	copy(clone.visited, a.visited)
+	return &clone
+}
+
+// Tour returns a copy of the nodes visited in the current tour
+func (a *GraphAnt) Tour() []int {
+	tour := make([]int, len(a.tour))
+	copy(tour, a.tour)
+	return tour
+}
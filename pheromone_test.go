@@ -0,0 +1,149 @@
+package hego
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func testPheromoneMatrix(t *testing.T, p PheromoneMatrix) {
+	if p.Size() != 3 {
+		t.Errorf("expected size 3, got %v", p.Size())
+	}
+	if p.Get(0, 1) != 1.0 {
+		t.Errorf("expected initial pheromone 1.0, got %v", p.Get(0, 1))
+	}
+	p.Deposit(0, 1, 2.0)
+	if p.Get(0, 1) != 3.0 || p.Get(1, 0) != 1.0 {
+		t.Errorf("deposit should only change edge 0->1, got %v and %v", p.Get(0, 1), p.Get(1, 0))
+	}
+	p.Evaporate(0.5, 0.6)
+	if p.Get(0, 1) != 1.5 {
+		t.Errorf("expected evaporated pheromone 1.5, got %v", p.Get(0, 1))
+	}
+	if p.Get(2, 2) != 0.6 {
+		t.Errorf("expected pheromone clamped to 0.6, got %v", p.Get(2, 2))
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Deposit(2, 1, 1.0)
+		}()
+	}
+	wg.Wait()
+	if p.Get(2, 1) != 10.6 {
+		t.Errorf("concurrent deposits should add up to 10.6, got %v", p.Get(2, 1))
+	}
+	dst := make([]float64, 1, 3)
+	row := p.(RowReader).Row(2, dst)
+	if len(row) != 3 || row[0] != 0.6 || row[1] != 10.6 || row[2] != 0.6 {
+		t.Errorf("expected row [0.6 10.6 0.6], got %v", row)
+	}
+	if &row[0] != &dst[0] {
+		t.Error("expected row to reuse dst with sufficient capacity")
+	}
+}
+
+// getOnlyPheromones hides the RowReader implementation of a matrix
+type getOnlyPheromones struct {
+	PheromoneMatrix
+}
+
+func TestDensePheromones(t *testing.T) {
+	testPheromoneMatrix(t, NewDensePheromones(3, 1.0))
+}
+
+func TestSparsePheromones(t *testing.T) {
+	p := NewSparsePheromones(3, 1.0)
+	testPheromoneMatrix(t, p)
+	p.Evaporate(0.1, 0.6)
+	p.Evaporate(0.1, 0.6)
+	if len(p.values) != 0 {
+		t.Errorf("evaporated edges should not be stored, got %v", p.values)
+	}
+}
+
+func TestWeights(t *testing.T) {
+	p := NewDensePheromones(3, 2.0)
+	heuristic := func(i, j int) float64 { return float64(j) }
+	weights := Weights(p, 0, heuristic, 1.0, 2.0, []bool{true, false, false})
+	if weights[0] != 0.0 || weights[1] != 2.0 || weights[2] != 8.0 {
+		t.Errorf("unexpected weights %v", weights)
+	}
+	weights = Weights(p, 0, nil, 2.0, 2.0, nil)
+	if weights[0] != 4.0 {
+		t.Errorf("expected weight 4.0 without heuristic, got %v", weights[0])
+	}
+	weights = Weights(getOnlyPheromones{p}, 0, heuristic, 1.0, 2.0, []bool{true, false, false})
+	if weights[0] != 0.0 || weights[1] != 2.0 || weights[2] != 8.0 {
+		t.Errorf("unexpected weights without RowReader %v", weights)
+	}
+}
+
+// circle returns a distance function for n cities placed on a unit circle
+func circle(n int) func(i, j int) float64 {
+	return func(i, j int) float64 {
+		ai, aj := 2*math.Pi*float64(i)/float64(n), 2*math.Pi*float64(j)/float64(n)
+		return math.Hypot(math.Cos(ai)-math.Cos(aj), math.Sin(ai)-math.Sin(aj))
+	}
+}
+
+func TestGraphAnt(t *testing.T) {
+	n := 8
+	distance := circle(n)
+	pheromones := NewDensePheromones(n, 1.0)
+	population := make([]Ant, 10)
+	for i := range population {
+		population[i] = NewGraphAnt(pheromones, distance)
+	}
+	settings := ACOSettings{}
+	settings.Evaporation = 0.9
+	settings.MinPheromone = 0.01
+	settings.MaxIterations = 50
+	res, err := ACO(population, settings)
+	if err != nil {
+		t.Errorf("ACO with graph ants should not fail, got: %v", err)
+	}
+	optimum := float64(n) * distance(0, 1)
+	if res.BestPerformance > optimum+1e-9 {
+		t.Errorf("expected optimal tour length %v, got %v", optimum, res.BestPerformance)
+	}
+	ant := population[0].(*GraphAnt)
+	tour := ant.Tour()
+	if len(tour) != n+1 || tour[0] != 0 || tour[n] != 0 {
+		t.Errorf("expected closed tour starting at 0, got %v", tour)
+	}
+	ant.Open = true
+	ant.Init()
+	for i := 1; i < n; i++ {
+		ant.Step(i)
+	}
+	if len(ant.Tour()) != n {
+		t.Errorf("expected open tour with %v nodes, got %v", n, ant.Tour())
+	}
+}
+
+func TestGraphAntTrails(t *testing.T) {
+	pheromones := NewSparsePheromones(3, 1.0)
+	a := NewGraphAnt(pheromones, circle(3))
+	a.Q = 2.0
+	a.Init()
+	a.Step(1)
+	a.LocalUpdate(0.5, 0.0)
+	if pheromones.Get(0, 1) != 0.5 || pheromones.Get(1, 0) != 0.5 {
+		t.Errorf("local update should halve the trail in both directions, got %v and %v", pheromones.Get(0, 1), pheromones.Get(1, 0))
+	}
+	if pheromones.Get(1, 2) != 1.0 {
+		t.Error("local update should only change the last edge")
+	}
+	a.LimitPheromone(0.4, 0.45)
+	if pheromones.Get(0, 1) != 0.8 || pheromones.Get(1, 2) != 0.9 {
+		t.Errorf("limits should be scaled by Q, got %v and %v", pheromones.Get(0, 1), pheromones.Get(1, 2))
+	}
+	a.ResetPheromone(1.0)
+	if pheromones.Get(0, 1) != 2.0 {
+		t.Errorf("reset value should be scaled by Q, got %v", pheromones.Get(0, 1))
+	}
+}
@@ -35,6 +35,9 @@ type TSSettings struct {
 	// is added to the tabu list. A produced neighbor wont be selected if he appears
 	// in the tabu list
 	TabuListSize int
+	// CacheSize is the number of objective values kept in a least recently used
+	// cache. It is only used for states implementing Keyed. 0 disables the cache
+	CacheSize int
 	Settings
 }
 
@@ -46,6 +49,9 @@ func (s *TSSettings) Verify() error {
 	if s.TabuListSize <= 1 {
 		return fmt.Errorf("size of Tabu List must be larger than 1, got %v", s.TabuListSize)
 	}
+	if s.CacheSize < 0 {
+		return fmt.Errorf("cache size cannot be negative, got %v", s.CacheSize)
+	}
 	return nil
 }
 
@@ -65,9 +71,16 @@ func TS(
 
 	logger := newLogger("Tabu Search", []string{"Iteration", "Objective", "Best"}, settings.Verbose, settings.MaxIterations)
 
+	cache := newEvalCache(settings.CacheSize)
 	evaluate := func(s TabuState) float64 {
+		if obj, ok := cache.lookup(s); ok {
+			res.CacheHits++
+			return obj
+		}
 		res.FuncEvaluations++
-		return s.Objective()
+		obj := s.Objective()
+		cache.store(s, obj)
+		return obj
 	}
 
 	state := initialState
@@ -0,0 +1,91 @@
+package hego
+
+// improvementEpsilon avoids endless loops due to rounding errors when
+// comparing tour lengths
+const improvementEpsilon = 1e-10
+
+// TourLength returns the summed distance between consecutive nodes of tour
+func TourLength(tour []int, distance func(i, j int) float64) float64 {
+	length := 0.0
+	for i := 0; i < len(tour)-1; i++ {
+		length += distance(tour[i], tour[i+1])
+	}
+	return length
+}
+
+// TwoOpt improves the tour in place by reversing segments as long as this
+// shortens the tour. The first and last node stay in place, so closed tours
+// (first node equals last node) and paths with fixed ends are supported.
+// distance must be symmetric. Returns true if the tour was improved
+func TwoOpt(tour []int, distance func(i, j int) float64) bool {
+	improved := false
+	for {
+		better := false
+		for i := 1; i < len(tour)-2; i++ {
+			for j := i + 1; j < len(tour)-1; j++ {
+				a, b, c, d := tour[i-1], tour[i], tour[j], tour[j+1]
+				delta := distance(a, c) + distance(b, d) - distance(a, b) - distance(c, d)
+				if delta < -improvementEpsilon {
+					for l, r := i, j; l < r; l, r = l+1, r-1 {
+						tour[l], tour[r] = tour[r], tour[l]
+					}
+					better = true
+				}
+			}
+		}
+		if !better {
+			return improved
+		}
+		improved = true
+	}
+}
+
+// OrOpt improves the tour in place by moving segments of up to three
+// consecutive nodes to a better position as long as this shortens the tour.
+// Like in TwoOpt, the first and last node stay in place. Returns true if the
+// tour was improved
+func OrOpt(tour []int, distance func(i, j int) float64) bool {
+	improved := false
+	for orOptMove(tour, distance) {
+		improved = true
+	}
+	return improved
+}
+
+// orOptMove performs the first improving segment move and returns true if one
+// was found
+func orOptMove(tour []int, distance func(i, j int) float64) bool {
+	for segLen := 1; segLen <= 3; segLen++ {
+		for i := 1; i+segLen < len(tour); i++ {
+			first, last := tour[i], tour[i+segLen-1]
+			prev, next := tour[i-1], tour[i+segLen]
+			removalGain := distance(prev, first) + distance(last, next) - distance(prev, next)
+			for k := 0; k < len(tour)-1; k++ {
+				// insertion between tour[k] and tour[k+1] must not touch the segment
+				if k >= i-1 && k < i+segLen {
+					continue
+				}
+				a, b := tour[k], tour[k+1]
+				insertionCost := distance(a, first) + distance(last, b) - distance(a, b)
+				if insertionCost-removalGain < -improvementEpsilon {
+					moveSegment(tour, i, segLen, k)
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// moveSegment moves tour[i:i+n] behind the node at position k
+func moveSegment(tour []int, i, n, k int) {
+	segment := make([]int, n)
+	copy(segment, tour[i:i+n])
+	rest := append(append(make([]int, 0, len(tour)), tour[:i]...), tour[i+n:]...)
+	if k >= i {
+		k -= n
+	}
+	copy(tour, rest[:k+1])
+	copy(tour[k+1:], segment)
+	copy(tour[k+1+n:], rest[k+1:])
+}
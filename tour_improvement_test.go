@@ -0,0 +1,113 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTourLength(t *testing.T) {
+	distance := func(i, j int) float64 { return math.Abs(float64(i - j)) }
+	if l := TourLength([]int{0, 2, 1, 0}, distance); l != 4.0 {
+		t.Errorf("expected tour length 4, got %v", l)
+	}
+}
+
+func TestTwoOpt(t *testing.T) {
+	n := 10
+	distance := circle(n)
+	optimum := float64(n) * distance(0, 1)
+	for run := 0; run < 10; run++ {
+		tour := append(append([]int{0}, rand.Perm(n-1)...), 0)
+		for i := 1; i < n; i++ {
+			tour[i]++
+		}
+		TwoOpt(tour, distance)
+		if tour[0] != 0 || tour[n] != 0 {
+			t.Errorf("TwoOpt should keep the ends of the tour in place, got %v", tour)
+		}
+		// 2-opt is optimal for points on a convex hull
+		if l := TourLength(tour, distance); math.Abs(l-optimum) > 1e-9 {
+			t.Errorf("expected optimal tour length %v, got %v for %v", optimum, l, tour)
+		}
+	}
+	if TwoOpt([]int{0, 1, 2, 0}, distance) {
+		t.Error("TwoOpt should not improve an optimal tour")
+	}
+}
+
+func TestOrOpt(t *testing.T) {
+	// nodes on a line, node 3 is out of place
+	distance := func(i, j int) float64 { return math.Abs(float64(i - j)) }
+	tour := []int{0, 3, 1, 2, 4}
+	if !OrOpt(tour, distance) {
+		t.Error("OrOpt should improve the tour")
+	}
+	expected := []int{0, 1, 2, 3, 4}
+	for i := range tour {
+		if tour[i] != expected[i] {
+			t.Errorf("expected tour %v, got %v", expected, tour)
+			break
+		}
+	}
+	tour = []int{0, 4, 5, 1, 2, 3, 6}
+	lengthBefore := TourLength(tour, distance)
+	OrOpt(tour, distance)
+	if TourLength(tour, distance) >= lengthBefore {
+		t.Errorf("OrOpt should shorten the tour, got %v", tour)
+	}
+	visited := make(map[int]bool)
+	for _, node := range tour {
+		visited[node] = true
+	}
+	if len(visited) != 7 {
+		t.Errorf("OrOpt should keep all nodes, got %v", tour)
+	}
+}
+
+func TestMoveSegment(t *testing.T) {
+	tour := []int{0, 1, 2, 3, 4, 5}
+	moveSegment(tour, 1, 2, 4)
+	expected := []int{0, 3, 4, 1, 2, 5}
+	for i := range tour {
+		if tour[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, tour)
+			break
+		}
+	}
+	tour = []int{0, 1, 2, 3, 4, 5}
+	moveSegment(tour, 3, 2, 0)
+	expected = []int{0, 3, 4, 1, 2, 5}
+	for i := range tour {
+		if tour[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, tour)
+			break
+		}
+	}
+}
+
+func TestGraphAntImprove(t *testing.T) {
+	n := 20
+	distance := circle(n)
+	pheromones := NewDensePheromones(n, 1.0)
+	population := make([]Ant, 2)
+	for i := range population {
+		ant := NewGraphAnt(pheromones, distance)
+		ant.LocalSearch = true
+		population[i] = ant
+	}
+	settings := ACOSettings{}
+	settings.Evaporation = 0.9
+	settings.MaxIterations = 3
+	res, err := ACO(population, settings)
+	if err != nil {
+		t.Errorf("ACO with local search should not fail, got: %v", err)
+	}
+	optimum := float64(n) * distance(0, 1)
+	if res.BestPerformance > optimum+1e-9 {
+		t.Errorf("expected local search to find optimal tour length %v, got %v", optimum, res.BestPerformance)
+	}
+	if l := TourLength(res.BestAnt.(*GraphAnt).Tour(), distance); math.Abs(l-res.BestPerformance) > 1e-9 {
+		t.Errorf("performance should match improved tour length %v, got %v", l, res.BestPerformance)
+	}
+}
@@ -0,0 +1,189 @@
+package hego
+
+import (
+	"fmt"
+	"time"
+)
+
+// GDSettings represents the algorithm settings for the great deluge algorithm
+type GDSettings struct {
+	// Level is the initial water level. Candidates below the level are accepted.
+	// When 0, the energy of the initial state is used
+	Level float64
+	// Rain is the amount the level is lowered by after each iteration. The level
+	// should reach the range of good energies during the last third of iterations
+	Rain float64
+	// CacheSize is the number of energies kept in a least recently used cache.
+	// It is only used for states implementing Keyed. 0 disables the cache
+	CacheSize int
+	Settings
+}
+
+// Verify returns an error if settings verification fails
+func (s *GDSettings) Verify() error {
+	if s.CacheSize < 0 {
+		return fmt.Errorf("cache size cannot be negative, got %v", s.CacheSize)
+	}
+	return (&GreatDeluge{Level: s.Level, Rain: s.Rain}).Verify()
+}
+
+// RRTSettings represents the algorithm settings for record-to-record travel
+type RRTSettings struct {
+	// Deviation is the absolute amount a candidate may be worse than the best
+	// energy found so far (the record) to be accepted
+	Deviation float64
+	// CacheSize is the number of energies kept in a least recently used cache.
+	// It is only used for states implementing Keyed. 0 disables the cache
+	CacheSize int
+	Settings
+}
+
+// Verify returns an error if settings verification fails
+func (s *RRTSettings) Verify() error {
+	if s.CacheSize < 0 {
+		return fmt.Errorf("cache size cannot be negative, got %v", s.CacheSize)
+	}
+	return (&RecordToRecord{Deviation: s.Deviation}).Verify()
+}
+
+// LAHCSettings represents the algorithm settings for late acceptance hill climbing
+type LAHCSettings struct {
+	// HistoryLength is the number of iterations a candidate is compared against
+	// in the past. 1 is plain hill climbing, longer histories explore more
+	HistoryLength int
+	// CacheSize is the number of energies kept in a least recently used cache.
+	// It is only used for states implementing Keyed. 0 disables the cache
+	CacheSize int
+	Settings
+}
+
+// Verify returns an error if settings verification fails
+func (s *LAHCSettings) Verify() error {
+	if s.CacheSize < 0 {
+		return fmt.Errorf("cache size cannot be negative, got %v", s.CacheSize)
+	}
+	return (&LateAcceptance{Length: s.HistoryLength}).Verify()
+}
+
+// GD performs the great deluge algorithm. Like SA it moves from state to
+// neighbor, but accepts a worse neighbor only when its energy is below a water
+// level that is lowered every iteration
+func GD(
+	initialState AnnealingState,
+	settings GDSettings,
+) (res SAResult, err error) {
+
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return
+	}
+	acceptance := GreatDeluge{Level: settings.Level, Rain: settings.Rain}
+	res = trajectorySearch("Great Deluge", initialState, &acceptance, settings.CacheSize, settings.Settings)
+	return
+}
+
+// RRT performs record-to-record travel. Like SA it moves from state to
+// neighbor, but accepts a neighbor when its energy is at most Deviation above
+// the best energy found so far
+func RRT(
+	initialState AnnealingState,
+	settings RRTSettings,
+) (res SAResult, err error) {
+
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return
+	}
+	acceptance := RecordToRecord{Deviation: settings.Deviation}
+	res = trajectorySearch("Record-to-Record Travel", initialState, &acceptance, settings.CacheSize, settings.Settings)
+	return
+}
+
+// LAHC performs late acceptance hill climbing. Like SA it moves from state to
+// neighbor, but accepts a neighbor when it is better than the current state or
+// the current state HistoryLength iterations ago
+func LAHC(
+	initialState AnnealingState,
+	settings LAHCSettings,
+) (res SAResult, err error) {
+
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return
+	}
+	acceptance := LateAcceptance{Length: settings.HistoryLength}
+	res = trajectorySearch("Late Acceptance Hill Climbing", initialState, &acceptance, settings.CacheSize, settings.Settings)
+	return
+}
+
+// trajectorySearch moves from state to neighbor for MaxIterations and keeps
+// the neighbors accepted by the acceptance criterion
+func trajectorySearch(
+	name string,
+	initialState AnnealingState,
+	acceptance AcceptanceCriterion,
+	cacheSize int,
+	settings Settings,
+) (res SAResult) {
+
+	start := time.Now()
+
+	logger := newLogger(name, []string{"Iteration", "Energy", "Best"}, settings.Verbose, settings.MaxIterations)
+
+	cache := newEvalCache(cacheSize)
+	evaluate := func(s AnnealingState) float64 {
+		if energy, ok := cache.lookup(s); ok {
+			res.CacheHits++
+			return energy
+		}
+		res.FuncEvaluations++
+		energy := s.Energy()
+		cache.store(s, energy)
+		return energy
+	}
+
+	state := initialState
+	energy := evaluate(state)
+	res.BestState, res.BestEnergy = state, energy
+	acceptance.Init(energy)
+
+	if settings.KeepHistory {
+		res.States = make([]AnnealingState, 0, settings.MaxIterations)
+		res.Energies = make([]float64, 0, settings.MaxIterations)
+	}
+
+	for i := 0; i < settings.MaxIterations; i++ {
+		candidate := state.Neighbor()
+		candidateEnergy := evaluate(candidate)
+		if acceptance.Accept(candidateEnergy, energy, res.BestEnergy) {
+			state = candidate
+			energy = candidateEnergy
+			if energy < res.BestEnergy {
+				res.BestState, res.BestEnergy = state, energy
+			}
+			if settings.KeepHistory {
+				res.States = append(res.States, candidate)
+				res.Energies = append(res.Energies, candidateEnergy)
+			}
+		}
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(energy),
+			fmt.Sprint(res.BestEnergy),
+		})
+	}
+
+	res.Runtime = time.Since(start)
+	res.Iterations = settings.MaxIterations
+	res.Energy = energy
+	res.State = state
+
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return
+}
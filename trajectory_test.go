@@ -0,0 +1,136 @@
+package hego
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVerifyTrajectorySettings(t *testing.T) {
+	gd := GDSettings{}
+	if gd.Verify() == nil {
+		t.Error("verification should fail for rain = 0.0")
+	}
+	gd.Rain = 0.1
+	gd.CacheSize = -1
+	if gd.Verify() == nil {
+		t.Error("verification should fail for negative cache size")
+	}
+	gd.CacheSize = 0
+	if err := gd.Verify(); err != nil {
+		t.Errorf("verification should pass, got: %v", err)
+	}
+
+	rrt := RRTSettings{Deviation: -1.0}
+	if rrt.Verify() == nil {
+		t.Error("verification should fail for negative deviation")
+	}
+	rrt.Deviation = 1.0
+	if err := rrt.Verify(); err != nil {
+		t.Errorf("verification should pass, got: %v", err)
+	}
+
+	lahc := LAHCSettings{}
+	if lahc.Verify() == nil {
+		t.Error("verification should fail for history length 0")
+	}
+	lahc.HistoryLength = 10
+	if err := lahc.Verify(); err != nil {
+		t.Errorf("verification should pass, got: %v", err)
+	}
+}
+
+func TestGD(t *testing.T) {
+	initialState := state(20.0)
+
+	settings := GDSettings{}
+	_, err := GD(initialState, settings)
+	if err == nil {
+		t.Error("GD should fail with invalid settings")
+	}
+	settings.Rain = 0.5
+	settings.MaxIterations = 1000
+	settings.Verbose = 1
+	settings.KeepHistory = true
+	res, err := GD(initialState, settings)
+	if err != nil {
+		t.Errorf("Error while running GD: %v", err)
+	}
+	if len(res.Energies) == 0 {
+		t.Error("Energies list should not be empty")
+	}
+	if res.Energy != res.Energies[len(res.Energies)-1] || res.State.Energy() != res.Energy {
+		t.Errorf("expected the last accepted state as result, got energy %v", res.Energy)
+	}
+	if res.BestEnergy > res.Energy || res.BestState.Energy() != res.BestEnergy {
+		t.Errorf("best energy %v should belong to the best state and not exceed %v", res.BestEnergy, res.Energy)
+	}
+	if math.Abs(res.Energy) > 0.5 {
+		t.Errorf("unexpected solution, got energy %v", res.Energy)
+	}
+}
+
+func TestRRT(t *testing.T) {
+	initialState := state(20.0)
+
+	settings := RRTSettings{Deviation: -1.0}
+	_, err := RRT(initialState, settings)
+	if err == nil {
+		t.Error("RRT should fail with invalid settings")
+	}
+	settings.Deviation = 1.0
+	settings.MaxIterations = 1000
+	settings.KeepHistory = true
+	res, err := RRT(initialState, settings)
+	if err != nil {
+		t.Errorf("Error while running RRT: %v", err)
+	}
+	for _, energy := range res.Energies {
+		if energy > 400.0+settings.Deviation {
+			t.Errorf("accepted energy %v exceeds the initial record by more than the deviation", energy)
+		}
+	}
+	// the final state may be worse than the record by up to the deviation
+	if res.Energy > res.BestEnergy+settings.Deviation {
+		t.Errorf("final energy %v exceeds the record %v by more than the deviation", res.Energy, res.BestEnergy)
+	}
+	if math.Abs(res.BestEnergy) > 0.5 {
+		t.Errorf("unexpected solution, got energy %v", res.BestEnergy)
+	}
+}
+
+func TestLAHC(t *testing.T) {
+	initialState := state(20.0)
+
+	settings := LAHCSettings{}
+	_, err := LAHC(initialState, settings)
+	if err == nil {
+		t.Error("LAHC should fail with invalid settings")
+	}
+	settings.HistoryLength = 5
+	settings.MaxIterations = 5000
+	res, err := LAHC(initialState, settings)
+	if err != nil {
+		t.Errorf("Error while running LAHC: %v", err)
+	}
+	if math.Abs(res.Energy) > 0.5 {
+		t.Errorf("unexpected solution, got energy %v", res.Energy)
+	}
+}
+
+func TestTrajectoryCache(t *testing.T) {
+	settings := LAHCSettings{HistoryLength: 5, CacheSize: 10}
+	settings.MaxIterations = 1000
+	res, err := LAHC(keyedState(10), settings)
+	if err != nil {
+		t.Errorf("Error while running LAHC with cache: %v", err)
+	}
+	if res.CacheHits == 0 {
+		t.Error("expected cache hits for repeated integer states")
+	}
+	if res.CacheHits+res.FuncEvaluations != settings.MaxIterations+1 {
+		t.Errorf("cache hits and evaluations should add up to the number of energy calls, got %v + %v", res.CacheHits, res.FuncEvaluations)
+	}
+	if res.BestEnergy != 0.0 {
+		t.Errorf("expected LAHC to find the optimum 0, got %v", res.BestEnergy)
+	}
+}
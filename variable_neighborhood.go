@@ -0,0 +1,158 @@
+package hego
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// VNSState describes the state during a variable neighborhood search
+type VNSState interface {
+	// Objective is the function to be minimized
+	Objective() float64
+	// Neighborhoods returns the number of neighborhood structures. They should
+	// be ordered from small (e.g. swapping adjacent elements) to large changes
+	Neighborhoods() int
+	// Neighbor produces a random state in the k-th neighborhood, k starts at 0
+	Neighbor(k int) VNSState
+}
+
+// VNSResult holds result and progress information about the variable neighborhood search
+type VNSResult struct {
+	// States holds the accepted states. Last element in this list is overall best solution
+	States []VNSState
+	// Objectives holds the objectives of the accepted states
+	Objectives    []float64
+	BestState     VNSState
+	BestObjective float64
+	Result
+}
+
+// VNSSettings describes the necessary settings for the variable neighborhood search
+type VNSSettings struct {
+	// LocalSearchSize is the number of consecutive neighbors without improvement
+	// after which the local search stops
+	LocalSearchSize int
+	// VariableDescent uses all neighborhoods in the local search (variable
+	// neighborhood descent). Otherwise only the first neighborhood is used
+	VariableDescent bool
+	// CacheSize is the number of objective values kept in a least recently used
+	// cache. It is only used for states implementing Keyed. 0 disables the cache
+	CacheSize int
+	Settings
+}
+
+// Verify returns an error if settings verification fails
+func (s *VNSSettings) Verify() error {
+	if s.LocalSearchSize < 1 {
+		return fmt.Errorf("local search size must be greater than 0, got %v", s.LocalSearchSize)
+	}
+	if s.CacheSize < 0 {
+		return fmt.Errorf("cache size cannot be negative, got %v", s.CacheSize)
+	}
+	return nil
+}
+
+// VNS performs variable neighborhood search. Every iteration shakes the
+// current state with a random neighbor of the k-th neighborhood and improves
+// it with a local search. When this yields a better state, it is accepted and
+// the search continues with the first neighborhood. Otherwise the next, larger
+// neighborhood is used, until all neighborhoods failed and the cycle restarts
+func VNS(
+	initialState VNSState,
+	settings VNSSettings,
+) (res VNSResult, err error) {
+
+	err = settings.Verify()
+	if err != nil {
+		err = fmt.Errorf("settings verification failed: %v", err)
+		return
+	}
+	neighborhoods := initialState.Neighborhoods()
+	if neighborhoods < 1 {
+		err = errors.New("state must provide at least one neighborhood")
+		return
+	}
+
+	start := time.Now()
+
+	logger := newLogger("Variable Neighborhood Search", []string{"Iteration", "Neighborhood", "Objective"}, settings.Verbose, settings.MaxIterations)
+
+	cache := newEvalCache(settings.CacheSize)
+	evaluate := func(s VNSState) float64 {
+		if obj, ok := cache.lookup(s); ok {
+			res.CacheHits++
+			return obj
+		}
+		res.FuncEvaluations++
+		obj := s.Objective()
+		cache.store(s, obj)
+		return obj
+	}
+
+	// localSearch samples neighbors until LocalSearchSize consecutive neighbors
+	// failed to improve in every neighborhood of the descent
+	descent := 1
+	if settings.VariableDescent {
+		descent = neighborhoods
+	}
+	localSearch := func(s VNSState, obj float64) (VNSState, float64) {
+		for l := 0; l < descent; {
+			improved := false
+			for failures := 0; failures < settings.LocalSearchSize; {
+				candidate := s.Neighbor(l)
+				if candidateObj := evaluate(candidate); candidateObj < obj {
+					s, obj = candidate, candidateObj
+					improved = true
+					failures = 0
+				} else {
+					failures++
+				}
+			}
+			if improved {
+				l = 0
+			} else {
+				l++
+			}
+		}
+		return s, obj
+	}
+
+	if settings.KeepHistory {
+		res.States = make([]VNSState, 0, settings.MaxIterations)
+		res.Objectives = make([]float64, 0, settings.MaxIterations)
+	}
+
+	state, obj := localSearch(initialState, evaluate(initialState))
+	k := 0
+	for i := 0; i < settings.MaxIterations; i++ {
+		shaken := state.Neighbor(k)
+		candidate, candidateObj := localSearch(shaken, evaluate(shaken))
+		if candidateObj < obj {
+			state, obj = candidate, candidateObj
+			k = 0
+			if settings.KeepHistory {
+				res.States = append(res.States, state)
+				res.Objectives = append(res.Objectives, obj)
+			}
+		} else {
+			k = (k + 1) % neighborhoods
+		}
+		logger.AddLine(i, []string{
+			fmt.Sprint(i),
+			fmt.Sprint(k),
+			fmt.Sprint(obj),
+		})
+	}
+	res.BestState = state
+	res.BestObjective = obj
+
+	res.Runtime = time.Since(start)
+	res.Iterations = settings.MaxIterations
+
+	logger.Flush()
+	if settings.Verbose > 0 {
+		fmt.Printf("Done after %v!\n", res.Runtime)
+	}
+	return
+}
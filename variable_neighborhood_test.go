@@ -0,0 +1,85 @@
+package hego
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ccssmnn/hego/mutate"
+)
+
+// tourState is a permutation of points on a circle, the optimal tour visits
+// them in order
+type tourState []int
+
+func (s tourState) Objective() float64 {
+	distance := circle(len(s))
+	return TourLength(append(append([]int{}, s...), s[0]), distance)
+}
+
+func (s tourState) Neighborhoods() int {
+	return 3
+}
+
+func (s tourState) Neighbor(k int) VNSState {
+	switch k {
+	case 0:
+		return tourState(mutate.SwapClose(s))
+	case 1:
+		return tourState(mutate.Swap(s))
+	default:
+		next := []int(s)
+		for i := 0; i < 3; i++ {
+			next = mutate.Swap(next)
+		}
+		return tourState(next)
+	}
+}
+
+func TestVerifyVNSSettings(t *testing.T) {
+	settings := VNSSettings{}
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with local search size 0")
+	}
+	settings.LocalSearchSize = 10
+	settings.CacheSize = -1
+	if settings.Verify() == nil {
+		t.Error("expected verification to fail with negative cache size")
+	}
+	settings.CacheSize = 0
+	if err := settings.Verify(); err != nil {
+		t.Errorf("expected verification to pass, got: %v", err)
+	}
+}
+
+func TestVNS(t *testing.T) {
+	n := 12
+	initialState := tourState(rand.Perm(n))
+	settings := VNSSettings{}
+	_, err := VNS(initialState, settings)
+	if err == nil {
+		t.Error("VNS should fail with invalid settings")
+	}
+	settings.LocalSearchSize = 50
+	settings.MaxIterations = 200
+	settings.KeepHistory = true
+	optimum := float64(n) * circle(n)(0, 1)
+	for _, descent := range []bool{false, true} {
+		settings.VariableDescent = descent
+		res, err := VNS(initialState, settings)
+		if err != nil {
+			t.Errorf("Error while running variable neighborhood search: %v", err)
+		}
+		if math.Abs(res.BestObjective-optimum) > 1e-9 {
+			t.Errorf("VNS with variable descent = %v did not find the optimal tour %v, got %v", descent, optimum, res.BestObjective)
+		}
+		for i := 1; i < len(res.Objectives); i++ {
+			if res.Objectives[i] >= res.Objectives[i-1] {
+				t.Error("expected accepted states to improve")
+			}
+		}
+		if res.Iterations != settings.MaxIterations {
+			t.Errorf("unexpected number of iterations %v", res.Iterations)
+		}
+	}
+}